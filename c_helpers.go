@@ -77,8 +77,12 @@ func freeDefines(defines []defineStruct) {
 			freeDefines(defines[i].subDefines)
 		}
 		defines[i].subDefines = nil
+		if defines[i].object != nil {
+			defines[i].object.free()
+			defines[i].object = nil
+		}
 		if defines[i].pbuf != nil {
-			freeBuffer(defines[i].pbuf, defines[i].dataType)
+			freeBufferArray(defines[i].pbuf, defines[i].dataType, defines[i].arraySize)
 			defines[i].pbuf = nil
 		}
 		if defines[i].length != nil {
@@ -96,8 +100,19 @@ func freeDefines(defines []defineStruct) {
 // freeBinds frees binds
 func freeBinds(binds []bindStruct) {
 	for _, bind := range binds {
+		if bind.temporaryLobConn != nil && bind.pbuf != nil {
+			err := bind.temporaryLobConn.ociLobFreeTemporary(*(**C.OCILobLocator)(bind.pbuf))
+			if err != nil {
+				bind.temporaryLobConn.logger.Print("free temporary LOB error: ", err)
+			}
+			bind.temporaryLobConn = nil
+		}
 		if bind.pbuf != nil {
-			freeBuffer(bind.pbuf, bind.dataType)
+			if bind.isArray || bind.plsqlArrayCurrent != nil {
+				freeBufferArray(bind.pbuf, bind.dataType, bind.arrayLength)
+			} else {
+				freeBuffer(bind.pbuf, bind.dataType)
+			}
 			bind.pbuf = nil
 		}
 		if bind.length != nil {
@@ -108,6 +123,18 @@ func freeBinds(binds []bindStruct) {
 			C.free(unsafe.Pointer(bind.indicator))
 			bind.indicator = nil
 		}
+		if bind.object != nil {
+			bind.object.free()
+			bind.object = nil
+		}
+		if bind.plsqlArrayCurrent != nil {
+			C.free(unsafe.Pointer(bind.plsqlArrayCurrent))
+			bind.plsqlArrayCurrent = nil
+		}
+		if bind.returning != nil {
+			C.oci8ReturningBindFree(bind.returning)
+			bind.returning = nil
+		}
 		bind.bindHandle = nil // freed by oci statement close
 	}
 }
@@ -128,9 +155,55 @@ func freeBuffer(buffer unsafe.Pointer, dataType C.ub2) {
 		C.OCIDescriptorFree(*(*unsafe.Pointer)(buffer), C.OCI_DTYPE_INTERVAL_DS)
 	case C.SQLT_INTERVAL_YM:
 		C.OCIDescriptorFree(*(*unsafe.Pointer)(buffer), C.OCI_DTYPE_INTERVAL_YM)
+	case C.SQLT_JSON:
+		C.OCIDescriptorFree(*(*unsafe.Pointer)(buffer), C.OCI_DTYPE_JSON)
+	case C.SQLT_VEC:
+		C.OCIDescriptorFree(*(*unsafe.Pointer)(buffer), C.OCI_DTYPE_VECTOR)
+	case C.SQLT_RSET:
+		C.OCIHandleFree(*(*unsafe.Pointer)(buffer), C.OCI_HTYPE_STMT)
+	default:
+		C.free(buffer)
+	}
+}
+
+// freeBufferArray calles OCIDescriptorFree or OCIHandleFree on each pointer in a C array of pointers,
+// then calles C free to free the array itself
+func freeBufferArray(buffer unsafe.Pointer, dataType C.ub2, count int) {
+	var descriptorType C.ub4
+	switch dataType {
+	case C.SQLT_CLOB, C.SQLT_BLOB:
+		descriptorType = C.OCI_DTYPE_LOB
+	case C.SQLT_TIMESTAMP:
+		descriptorType = C.OCI_DTYPE_TIMESTAMP
+	case C.SQLT_TIMESTAMP_TZ:
+		descriptorType = C.OCI_DTYPE_TIMESTAMP_TZ
+	case C.SQLT_TIMESTAMP_LTZ:
+		descriptorType = C.OCI_DTYPE_TIMESTAMP_LTZ
+	case C.SQLT_INTERVAL_DS:
+		descriptorType = C.OCI_DTYPE_INTERVAL_DS
+	case C.SQLT_INTERVAL_YM:
+		descriptorType = C.OCI_DTYPE_INTERVAL_YM
+	case C.SQLT_JSON:
+		descriptorType = C.OCI_DTYPE_JSON
+	case C.SQLT_VEC:
+		descriptorType = C.OCI_DTYPE_VECTOR
 	case C.SQLT_RSET:
-		C.OCIDescriptorFree(*(*unsafe.Pointer)(buffer), C.OCI_HTYPE_STMT)
+		descriptorType = C.OCI_HTYPE_STMT
 	default:
 		C.free(buffer)
+		return
+	}
+
+	pointers := (*[1 << 27]unsafe.Pointer)(buffer)[:count:count]
+	for i := 0; i < count; i++ {
+		if pointers[i] == nil {
+			continue
+		}
+		if dataType == C.SQLT_RSET {
+			C.OCIHandleFree(pointers[i], descriptorType)
+		} else {
+			C.OCIDescriptorFree(pointers[i], descriptorType)
+		}
 	}
+	C.free(buffer)
 }
@@ -0,0 +1,283 @@
+package oci8
+
+// #include "oci8.go.h"
+import "C"
+
+import (
+	"fmt"
+	"reflect"
+	"time"
+	"unsafe"
+)
+
+// Scan implements sql.Scanner, a null collection sets Elements to nil
+func (collection *Collection) Scan(src interface{}) error {
+	switch value := src.(type) {
+	case nil:
+		collection.Elements = nil
+	case Collection:
+		*collection = value
+	default:
+		return fmt.Errorf("can not scan %T into Collection", src)
+	}
+	return nil
+}
+
+// Scan implements sql.Scanner, setting the slice to the collection elements
+func (collectionSlice *CollectionSlice) Scan(src interface{}) error {
+	rv := reflect.ValueOf(collectionSlice.Slice)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return fmt.Errorf("CollectionSlice Slice is %T, expected a pointer to a slice", collectionSlice.Slice)
+	}
+
+	switch value := src.(type) {
+	case nil:
+		return setSliceFromCollection(rv.Elem(), nil)
+	case Collection:
+		return setSliceFromCollection(rv.Elem(), &value)
+	}
+	return fmt.Errorf("can not scan %T into CollectionSlice", src)
+}
+
+// collection returns a Collection with the elements set from the slice
+func (collectionSlice *CollectionSlice) collection() (*Collection, error) {
+	if collectionSlice.Slice == nil {
+		return &Collection{TypeName: collectionSlice.TypeName}, nil
+	}
+	return collectionFromSlice(collectionSlice.TypeName, reflect.ValueOf(collectionSlice.Slice))
+}
+
+// isCollectionSlice returns true if values of the type are converted to and from nested collections.
+// A []byte is a RAW value, not a collection.
+func isCollectionSlice(rt reflect.Type) bool {
+	for rt.Kind() == reflect.Ptr {
+		rt = rt.Elem()
+	}
+	return rt.Kind() == reflect.Slice && rt.Elem().Kind() != reflect.Uint8
+}
+
+// collectionFromValue returns a Collection from a Collection, CollectionSlice, or a slice.
+// A nil value returns nil.
+func collectionFromValue(typeName string, value interface{}) (*Collection, error) {
+	switch collection := value.(type) {
+	case nil:
+		return nil, nil
+	case Collection:
+		return &collection, nil
+	case *Collection:
+		return collection, nil
+	case CollectionSlice:
+		return collection.collection()
+	case *CollectionSlice:
+		return collection.collection()
+	}
+	return collectionFromSlice(typeName, reflect.ValueOf(value))
+}
+
+// collectionFromSlice returns a Collection with the elements set from a slice or pointer to a slice.
+// A nil slice returns a null Collection.
+func collectionFromSlice(typeName string, rv reflect.Value) (*Collection, error) {
+	for rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return &Collection{TypeName: typeName}, nil
+		}
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return nil, fmt.Errorf("%v is not a slice", rv.Type())
+	}
+	if rv.Kind() == reflect.Slice && rv.IsNil() {
+		return &Collection{TypeName: typeName}, nil
+	}
+
+	collection := &Collection{TypeName: typeName, Elements: make([]interface{}, rv.Len())}
+	for i := 0; i < rv.Len(); i++ {
+		element := rv.Index(i)
+		switch {
+		case isObjectStruct(element.Type()):
+			nested, err := objectFromStruct("", element)
+			if err != nil {
+				return nil, fmt.Errorf("element %v - error: %v", i, err)
+			}
+			collection.Elements[i] = *nested
+		case isCollectionSlice(element.Type()):
+			nested, err := collectionFromSlice("", element)
+			if err != nil {
+				return nil, fmt.Errorf("element %v - error: %v", i, err)
+			}
+			collection.Elements[i] = *nested
+		default:
+			collection.Elements[i] = element.Interface()
+		}
+	}
+
+	return collection, nil
+}
+
+// setSliceFromCollection sets a slice, or pointer to a slice, to the elements of a collection.
+// A null collection sets the slice to nil.
+func setSliceFromCollection(dest reflect.Value, collection *Collection) error {
+	if collection == nil || collection.Elements == nil {
+		dest.Set(reflect.Zero(dest.Type()))
+		return nil
+	}
+	if dest.Kind() == reflect.Ptr {
+		if dest.IsNil() {
+			dest.Set(reflect.New(dest.Type().Elem()))
+		}
+		return setSliceFromCollection(dest.Elem(), collection)
+	}
+	if dest.Kind() != reflect.Slice {
+		return fmt.Errorf("%v is not a slice", dest.Type())
+	}
+
+	values := reflect.MakeSlice(dest.Type(), len(collection.Elements), len(collection.Elements))
+	for i, element := range collection.Elements {
+		err := setFieldValue(values.Index(i), element)
+		if err != nil {
+			return fmt.Errorf("element %v - error: %v", i, err)
+		}
+	}
+
+	dest.Set(values)
+	return nil
+}
+
+// collectionValue returns the Collection of a not null collection instance
+func (objType *objectType) collectionValue(instance unsafe.Pointer) (interface{}, error) {
+	conn := objType.conn
+	var size C.sb4
+	result := C.OCICollSize(conn.env, conn.errHandle, (*C.OCIColl)(instance), &size)
+	err := conn.getError(result)
+	if err != nil {
+		return nil, fmt.Errorf("collection size error: %v", err)
+	}
+
+	collection := Collection{
+		TypeName: objType.name,
+		Elements: make([]interface{}, 0, int(size)),
+	}
+	for i := 0; i < int(size); i++ {
+		var exists C.boolean
+		var element unsafe.Pointer
+		var elementNullStruct unsafe.Pointer
+		result = C.OCICollGetElem(
+			conn.env,               // environment handle
+			conn.errHandle,         // error handle
+			(*C.OCIColl)(instance), // the collection instance
+			C.sb4(i),               // the element index
+			&exists,                // false if a nested table element has been deleted
+			&element,               // pointer to the element value
+			&elementNullStruct,     // the null indicator of the element
+		)
+		err = conn.getError(result)
+		if err != nil {
+			return nil, fmt.Errorf("collection type %v element %v error: %v", objType.name, i, err)
+		}
+		if exists != C.TRUE {
+			continue
+		}
+		if elementNullStruct != nil && *(*C.OCIInd)(elementNullStruct) == C.OCI_IND_NULL {
+			collection.Elements = append(collection.Elements, nil)
+			continue
+		}
+
+		value, err := conn.attributeGoValue(objType.element, element, elementNullStruct)
+		if err != nil {
+			return nil, fmt.Errorf("collection type %v element %v error: %v", objType.name, i, err)
+		}
+		collection.Elements = append(collection.Elements, value)
+	}
+
+	return collection, nil
+}
+
+// newCollection calls OCIObjectNew to make a collection instance with the elements of collection,
+// then returns the instance and its null indicator.
+// A nil collection or nil Elements makes a null collection. The instance must be freed with OCIObjectFree.
+func (objType *objectType) newCollection(collection *Collection) (unsafe.Pointer, unsafe.Pointer, error) {
+	conn := objType.conn
+	instance, nullStruct, err := objType.objectNew()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if collection == nil || collection.Elements == nil {
+		*(*C.OCIInd)(nullStruct) = C.OCI_IND_NULL
+		return instance, nullStruct, nil
+	}
+	*(*C.OCIInd)(nullStruct) = C.OCI_IND_NOTNULL
+
+	for i, element := range collection.Elements {
+		err = objType.appendElement(instance, element)
+		if err != nil {
+			C.OCIObjectFree(conn.env, conn.errHandle, instance, C.OCI_OBJECTFREE_FORCE)
+			return nil, nil, fmt.Errorf("collection type %v element %v error: %v", objType.name, i, err)
+		}
+	}
+
+	return instance, nullStruct, nil
+}
+
+// appendElement converts a Go value to the element type then calls OCICollAppend
+func (objType *objectType) appendElement(instance unsafe.Pointer, value interface{}) error {
+	conn := objType.conn
+
+	indicator := C.OCIInd(C.OCI_IND_NOTNULL)
+	elementValue, elementNullStruct, freeValue, err := objType.attributeValue(objType.element, value)
+	if err != nil {
+		return err
+	}
+	if elementValue == nil {
+		// OCICollAppend copies the element value even for a null element
+		indicator = C.OCI_IND_NULL
+		elementValue, elementNullStruct, freeValue, err = objType.nullElementValue()
+		if err != nil {
+			return err
+		}
+	}
+	if freeValue != nil {
+		defer freeValue()
+	}
+	if elementNullStruct == nil {
+		elementNullStruct = unsafe.Pointer(&indicator)
+	}
+
+	result := C.OCICollAppend(
+		conn.env,               // environment handle
+		conn.errHandle,         // error handle
+		elementValue,           // the element value
+		elementNullStruct,      // the null indicator of the element
+		(*C.OCIColl)(instance), // the collection instance
+	)
+	return conn.getError(result)
+}
+
+// nullElementValue returns an element value for a null element.
+// Object and collection elements are null instances, so their null indicator is returned as well.
+func (objType *objectType) nullElementValue() (unsafe.Pointer, unsafe.Pointer, func(), error) {
+	conn := objType.conn
+	element := objType.element
+
+	var value interface{}
+	switch element.typeCode {
+	case C.OCI_TYPECODE_OBJECT, C.OCI_TYPECODE_NAMEDCOLLECTION:
+		instance, nullStruct, err := element.objectType.newValue(nil)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		return instance, nullStruct, func() {
+			C.OCIObjectFree(conn.env, conn.errHandle, instance, C.OCI_OBJECTFREE_FORCE)
+		}, nil
+	case C.OCI_TYPECODE_VARCHAR2, C.OCI_TYPECODE_VARCHAR, C.OCI_TYPECODE_CHAR:
+		value = ""
+	case C.OCI_TYPECODE_RAW:
+		value = []byte{}
+	case C.OCI_TYPECODE_DATE, C.OCI_TYPECODE_TIMESTAMP, C.OCI_TYPECODE_TIMESTAMP_TZ, C.OCI_TYPECODE_TIMESTAMP_LTZ:
+		value = time.Time{}
+	default:
+		value = int64(0)
+	}
+
+	return objType.attributeValue(element, value)
+}
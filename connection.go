@@ -6,9 +6,11 @@ import "C"
 import (
 	"bytes"
 	"context"
+	"database/sql"
 	"database/sql/driver"
 	"errors"
 	"fmt"
+	"math"
 	"time"
 	"unsafe"
 )
@@ -19,10 +21,9 @@ func (conn *Conn) Ping(ctx context.Context) error {
 		return ctx.Err()
 	}
 
-	done := make(chan struct{})
-	go conn.ociBreakDone(ctx, done)
+	conn.callBegin(ctx)
 	result := C.OCIPing(conn.svc, conn.errHandle, C.OCI_DEFAULT)
-	close(done)
+	conn.callEnd()
 
 	if result == C.OCI_SUCCESS || result == C.OCI_SUCCESS_WITH_INFO {
 		return nil
@@ -35,9 +36,67 @@ func (conn *Conn) Ping(ctx context.Context) error {
 	}
 
 	conn.logger.Print("Ping error: ", err)
+	conn.badConn = true
 	return driver.ErrBadConn
 }
 
+// IsValid returns false if the connection has been marked bad, so it will be discarded instead of reused
+func (conn *Conn) IsValid() bool {
+	return !conn.closed && !conn.badConn
+}
+
+// ResetSession is called before a connection is reused.
+// Rolls back any pending transaction and if reset_package is set, resets the PL/SQL package state.
+// Returns driver.ErrBadConn if the connection has been marked bad or the session could not be reset.
+func (conn *Conn) ResetSession(ctx context.Context) error {
+	if conn.badConn {
+		return driver.ErrBadConn
+	}
+
+	if conn.inTransaction {
+		tx := conn.tx
+		conn.inTransaction = false
+		conn.tx = nil
+		conn.callBegin(ctx)
+		result := C.OCITransRollback(conn.svc, conn.errHandle, 0)
+		conn.callEnd()
+		if tx != nil && tx.transHandle != nil {
+			conn.ociTransHandleFree(tx.transHandle)
+			tx.transHandle = nil
+		}
+		if result != C.OCI_SUCCESS {
+			conn.logger.Print("ResetSession rollback error: ", conn.getError(result))
+			conn.badConn = true
+			return driver.ErrBadConn
+		}
+	}
+
+	if conn.resetPackage {
+		err := conn.execQuery(ctx, "begin DBMS_SESSION.RESET_PACKAGE; end;")
+		if err != nil {
+			conn.logger.Print("ResetSession reset package error: ", err)
+			conn.badConn = true
+			return driver.ErrBadConn
+		}
+	}
+
+	return nil
+}
+
+// execQuery prepares and executes a query without binds
+func (conn *Conn) execQuery(ctx context.Context, query string) error {
+	stmt, err := conn.PrepareContext(ctx, query)
+	if err != nil {
+		return err
+	}
+	_, err = stmt.(*Stmt).ExecContext(ctx, nil)
+	closeErr := stmt.Close()
+	if err != nil {
+		return err
+	}
+	return closeErr
+}
+
 // Close a connection
 func (conn *Conn) Close() error {
 	if conn.closed {
@@ -45,6 +104,15 @@ func (conn *Conn) Close() error {
 	}
 	conn.closed = true
 
+	if conn.callWatch != nil {
+		close(conn.callWatch)
+		conn.callWatch = nil
+	}
+
+	if conn.sessionPool != nil {
+		return conn.sessionPoolRelease()
+	}
+
 	var err error
 	if useOCISessionBegin {
 		if rv := C.OCISessionEnd(
@@ -92,9 +160,7 @@ func (conn *Conn) Prepare(query string) (driver.Stmt, error) {
 
 // PrepareContext prepares a query with context
 func (conn *Conn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
-	if conn.enableQMPlaceholders {
-		query = placeholders(query)
-	}
+	query, ordinalBinds := placeholders(query, conn.placeholderStyles)
 
 	queryP := cString(query)
 	defer C.free(unsafe.Pointer(queryP))
@@ -105,9 +171,34 @@ func (conn *Conn) PrepareContext(ctx context.Context, query string) (driver.Stmt
 		return nil, ctx.Err()
 	}
 
-	done := make(chan struct{})
-	go conn.ociBreakDone(ctx, done)
-	defer func() { close(done) }()
+	conn.callBegin(ctx)
+	defer conn.callEnd()
+
+	if conn.stmtCacheSize > 0 {
+		// statement cache is keyed by the statement text
+		if rv := C.OCIStmtPrepare2(
+			conn.svc,                            // service context handle
+			stmt,                                // pointer to the statement handle returned
+			conn.errHandle,                      // error handle
+			queryP,                              // statement text
+			C.ub4(len(query)),                   // statement text length
+			queryP,                              // key to be used for searching the statement in the statement cache
+			C.ub4(len(query)),                   // length of the key
+			C.ub4(C.OCI_NTV_SYNTAX),             // syntax - OCI_NTV_SYNTAX: syntax depends upon the version of the server
+			C.ub4(C.OCI_PREP2_CACHE_SEARCHONLY), // mode - OCI_PREP2_CACHE_SEARCHONLY: only return a statement found in the statement cache
+		); rv == C.OCI_SUCCESS {
+			conn.stmtCacheHits++
+			return &Stmt{conn: conn, stmt: *stmt, ctx: ctx, cacheKey: query, ordinalBinds: ordinalBinds}, nil
+		}
+		conn.stmtCacheMisses++
+	}
+
+	var key *C.OraText
+	var keyLength C.ub4
+	if conn.stmtCacheSize > 0 {
+		key = queryP
+		keyLength = C.ub4(len(query))
+	}
 
 	if rv := C.OCIStmtPrepare2(
 		conn.svc,                // service context handle
@@ -115,15 +206,30 @@ func (conn *Conn) PrepareContext(ctx context.Context, query string) (driver.Stmt
 		conn.errHandle,          // error handle
 		queryP,                  // statement text
 		C.ub4(len(query)),       // statement text length
-		nil,                     // key to be used for searching the statement in the statement cache
-		C.ub4(0),                // length of the key
+		key,                     // key to be used for searching the statement in the statement cache
+		keyLength,               // length of the key
 		C.ub4(C.OCI_NTV_SYNTAX), // syntax - OCI_NTV_SYNTAX: syntax depends upon the version of the server
 		C.ub4(C.OCI_DEFAULT),    // mode
 	); rv != C.OCI_SUCCESS {
 		return nil, conn.getError(rv)
 	}
 
-	return &Stmt{conn: conn, stmt: *stmt, ctx: ctx}, nil
+	stmtStruct := &Stmt{conn: conn, stmt: *stmt, ctx: ctx, ordinalBinds: ordinalBinds}
+	if conn.stmtCacheSize > 0 {
+		stmtStruct.cacheKey = query
+	}
+
+	return stmtStruct, nil
+}
+
+// StmtCacheStats returns the statement cache statistics of the connection.
+// Use sql.Conn Raw to get the *Conn.
+func (conn *Conn) StmtCacheStats() StmtCacheStats {
+	return StmtCacheStats{
+		Size:   int(conn.stmtCacheSize),
+		Hits:   conn.stmtCacheHits,
+		Misses: conn.stmtCacheMisses,
+	}
 }
 
 // Begin starts a transaction
@@ -137,7 +243,16 @@ func (conn *Conn) BeginTx(ctx context.Context, txOptions driver.TxOptions) (driv
 		return nil, ctx.Err()
 	}
 
-	if conn.transactionMode != C.OCI_TRANS_READWRITE {
+	transactionMode, err := conn.transactionModeFromOptions(txOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	if xidCtx, ok := ctx.Value(xidContextKey{}).(*xidContext); ok {
+		return conn.beginTxXID(ctx, xidCtx, transactionMode)
+	}
+
+	if transactionMode != C.OCI_TRANS_READWRITE {
 		// transaction handle
 		trans, _, err := conn.ociHandleAlloc(C.OCI_HTYPE_TRANS, 0)
 		if err != nil {
@@ -159,7 +274,7 @@ func (conn *Conn) BeginTx(ctx context.Context, txOptions driver.TxOptions) (driv
 			conn.svc,
 			conn.errHandle,
 			0,
-			conn.transactionMode, // mode is: C.OCI_TRANS_SERIALIZABLE, C.OCI_TRANS_READWRITE, or C.OCI_TRANS_READONLY
+			transactionMode, // mode is: C.OCI_TRANS_SERIALIZABLE, C.OCI_TRANS_READWRITE, or C.OCI_TRANS_READONLY
 		); rv != C.OCI_SUCCESS {
 			return nil, conn.getError(rv)
 		}
@@ -167,8 +282,33 @@ func (conn *Conn) BeginTx(ctx context.Context, txOptions driver.TxOptions) (driv
 	}
 
 	conn.inTransaction = true
+	conn.tx = &Tx{conn: conn}
 
-	return &Tx{conn: conn}, nil
+	return conn.tx, nil
+}
+
+// transactionModeFromOptions returns the OCITransStart mode for the transaction options.
+// The default isolation level uses the isolation DSN setting.
+func (conn *Conn) transactionModeFromOptions(txOptions driver.TxOptions) (C.ub4, error) {
+	switch sql.IsolationLevel(txOptions.Isolation) {
+	case sql.LevelDefault:
+		if txOptions.ReadOnly {
+			return C.OCI_TRANS_READONLY, nil
+		}
+		return conn.transactionMode, nil
+	case sql.LevelReadCommitted:
+		if txOptions.ReadOnly {
+			// Oracle read only transactions see the data as of the start of the transaction
+			return 0, errors.New("read only transaction is not supported with isolation level Read Committed")
+		}
+		return C.OCI_TRANS_READWRITE, nil
+	case sql.LevelSerializable:
+		if txOptions.ReadOnly {
+			return C.OCI_TRANS_READONLY, nil
+		}
+		return C.OCI_TRANS_SERIALIZABLE, nil
+	}
+	return 0, fmt.Errorf("isolation level %v is not supported", sql.IsolationLevel(txOptions.Isolation))
 }
 
 // getError gets error from return result (sword) or OCIError
@@ -190,21 +330,8 @@ func (conn *Conn) getError(result C.sword) error {
 		return ErrOCIStillExecuting
 	case C.OCI_ERROR:
 		errorCode, err := conn.ociGetError()
-		switch errorCode {
-		/*
-			bad connection errors:
-			ORA-00028: your session has been killed
-			ORA-01012: Not logged on
-			ORA-01033: ORACLE initialization or shutdown in progress
-			ORA-01034: ORACLE not available
-			ORA-01089: immediate shutdown in progress - no operations are permitted
-			ORA-03113: end-of-file on communication channel
-			ORA-03114: Not Connected to Oracle
-			ORA-03135: connection lost contact
-			ORA-12528: TNS:listener: all appropriate instances are blocking new connections
-			ORA-12537: TNS:connection closed
-		*/
-		case 28, 1012, 1033, 1034, 1089, 3113, 3114, 3135, 12528, 12537:
+		if isBadConnCode(errorCode) {
+			conn.badConn = true
 			return driver.ErrBadConn
 		}
 		return err
@@ -212,27 +339,109 @@ func (conn *Conn) getError(result C.sword) error {
 	return fmt.Errorf("received result code %d", result)
 }
 
-// ociGetError calls OCIErrorGet then returs error code and text
+// isBadConnCode returns true if the ORA error code means the connection is no longer usable
+func isBadConnCode(errorCode int) bool {
+	switch errorCode {
+	/*
+		bad connection errors:
+		ORA-00028: your session has been killed
+		ORA-01012: Not logged on
+		ORA-01033: ORACLE initialization or shutdown in progress
+		ORA-01034: ORACLE not available
+		ORA-01089: immediate shutdown in progress - no operations are permitted
+		ORA-03113: end-of-file on communication channel
+		ORA-03114: Not Connected to Oracle
+		ORA-03135: connection lost contact
+		ORA-12528: TNS:listener: all appropriate instances are blocking new connections
+		ORA-12537: TNS:connection closed
+	*/
+	case 28, 1012, 1033, 1034, 1089, 3113, 3114, 3135, 12528, 12537:
+		return true
+	}
+	return false
+}
+
+// ociGetError calls OCIErrorGet then returs error code and *OraError
 func (conn *Conn) ociGetError() (int, error) {
-	var errorCode C.sb4
+	return ociGetErrorFromHandle(conn.errHandle)
+}
+
+// ociGetErrorFromHandle calls OCIErrorGet on the error handle for each error record then returs error code and *OraError
+func ociGetErrorFromHandle(errHandle *C.OCIError) (int, error) {
+	var oraError *OraError
 	errorText := make([]byte, 1024)
 
-	result := C.OCIErrorGet(
-		unsafe.Pointer(conn.errHandle), // error handle
-		1,                              // status record number, starts from 1
-		nil,                            // sqlstate, not supported in release 8.x or later
-		&errorCode,                     // error code
-		(*C.OraText)(&errorText[0]),    // error message text
-		1024,                           // size of the buffer provided in number of bytes
-		C.OCI_HTYPE_ERROR,              // type of the handle (OCI_HTYPE_ERR or OCI_HTYPE_ENV)
-	)
-	if result != C.OCI_SUCCESS {
+	for recordNumber := C.ub4(1); ; recordNumber++ {
+		var errorCode C.sb4
+		result := C.OCIErrorGet(
+			unsafe.Pointer(errHandle),   // error handle
+			recordNumber,                // status record number, starts from 1
+			nil,                         // sqlstate, not supported in release 8.x or later
+			&errorCode,                  // error code
+			(*C.OraText)(&errorText[0]), // error message text
+			1024,                        // size of the buffer provided in number of bytes
+			C.OCI_HTYPE_ERROR,           // type of the handle (OCI_HTYPE_ERR or OCI_HTYPE_ENV)
+		)
+		if result != C.OCI_SUCCESS {
+			// OCI_NO_DATA after the last record
+			break
+		}
+
+		index := bytes.IndexByte(errorText, 0)
+		record := OraErrorRecord{Code: int(errorCode), Message: string(errorText[:index])}
+		if oraError == nil {
+			oraError = &OraError{Code: record.Code, Message: record.Message}
+		}
+		oraError.Records = append(oraError.Records, record)
+	}
+
+	if oraError == nil {
 		return 3114, errors.New("OCIErrorGet failed")
 	}
 
-	index := bytes.IndexByte(errorText, 0)
+	return oraError.Code, oraError
+}
+
+// Error returns the error message of the first error record
+func (oraError *OraError) Error() string {
+	return oraError.Message
+}
+
+// Is returns true if the error code is classified as target, for use with errors.Is.
+// The targets are ErrUniqueViolation, ErrDeadlock, ErrSerializationFailure, ErrResourceBusy,
+// ErrValueTooLarge, and driver.ErrBadConn.
+func (oraError *OraError) Is(target error) bool {
+	return oraErrorCodeIs(oraError.Code, target)
+}
 
-	return int(errorCode), errors.New(string(errorText[:index]))
+// oraErrorCodeIs returns true if the ORA error code is classified as target
+func oraErrorCodeIs(errorCode int, target error) bool {
+	switch target {
+	case ErrUniqueViolation:
+		// ORA-00001: unique constraint violated
+		return errorCode == 1
+	case ErrDeadlock:
+		// ORA-00060: deadlock detected while waiting for resource
+		return errorCode == 60
+	case ErrSerializationFailure:
+		// ORA-08177: can't serialize access for this transaction
+		return errorCode == 8177
+	case ErrResourceBusy:
+		// ORA-00054: resource busy and acquire with NOWAIT specified or timeout expired
+		// ORA-30006: resource busy; acquire with WAIT timeout expired
+		return errorCode == 54 || errorCode == 30006
+	case ErrValueTooLarge:
+		// ORA-01401: inserted value too large for column
+		// ORA-01438: value larger than specified precision allowed for this column
+		// ORA-12899: value too large for column
+		return errorCode == 1401 || errorCode == 1438 || errorCode == 12899
+	case driver.ErrBadConn:
+		return isBadConnCode(errorCode)
+	case context.DeadlineExceeded:
+		// ORA-03156: OCI call timed out
+		return errorCode == 3156
+	}
+	return false
 }
 
 // ociAttrGet calls OCIAttrGet with OCIParam then returns attribute size and error.
@@ -335,6 +544,33 @@ func (conn *Conn) ociDescriptorAlloc(descriptorType C.ub4, size C.size_t) (*unsa
 	return descriptor, nil, nil
 }
 
+// ociDescriptorAllocArray calls OCIDescriptorAlloc count times then returns
+// a C array of the descriptor pointers and error. The array must be freed with freeBufferArray.
+func (conn *Conn) ociDescriptorAllocArray(descriptorType C.ub4, count int) (unsafe.Pointer, error) {
+	array := C.calloc(C.size_t(count), C.size_t(sizeOfNilPointer))
+	pointers := (*[1 << 27]unsafe.Pointer)(array)[:count:count]
+
+	for i := 0; i < count; i++ {
+		result := C.OCIDescriptorAlloc(
+			unsafe.Pointer(conn.env), // An environment handle
+			&pointers[i],             // Returns a descriptor or LOB locator of desired type
+			descriptorType,           // Specifies the type of descriptor or LOB locator to be allocated
+			0,                        // Specifies an amount of user memory to be allocated for use by the application for the lifetime of the descriptor
+			nil,                      // Returns a pointer to the user memory of size xtramem_sz allocated by the call for the user for the lifetime of the descriptor
+		)
+		err := conn.getError(result)
+		if err != nil {
+			for j := 0; j < i; j++ {
+				C.OCIDescriptorFree(pointers[j], descriptorType)
+			}
+			C.free(array)
+			return nil, err
+		}
+	}
+
+	return array, nil
+}
+
 // ociLobCreateTemporary calls OCILobCreateTemporary then returns error
 func (conn *Conn) ociLobCreateTemporary(lobLocator *C.OCILobLocator, form C.ub1, lobType C.ub1) error {
 
@@ -352,6 +588,17 @@ func (conn *Conn) ociLobCreateTemporary(lobLocator *C.OCILobLocator, form C.ub1,
 	return conn.getError(result)
 }
 
+// ociLobFreeTemporary calls OCILobFreeTemporary then returns error
+func (conn *Conn) ociLobFreeTemporary(lobLocator *C.OCILobLocator) error {
+	result := C.OCILobFreeTemporary(
+		conn.svc,       // service context handle
+		conn.errHandle, // error handle
+		lobLocator,     // locator that points to the temporary LOB
+	)
+
+	return conn.getError(result)
+}
+
 // ociLobRead calls OCILobRead then returns lob bytes and error.
 func (conn *Conn) ociLobRead(lobLocator *C.OCILobLocator, form C.ub1) ([]byte, error) {
 	buffer := make([]byte, 0)
@@ -581,16 +828,79 @@ func appendSmallInt(slice []byte, num int) []byte {
 	return append(slice, byte('0'+num/10), byte('0'+(num%10)))
 }
 
-// ociBreakDone calls OCIBreak if ctx.Done is finished before done chan is closed
-func (conn *Conn) ociBreakDone(ctx context.Context, done chan struct{}) {
-	select {
-	case <-done:
-	case <-ctx.Done():
-		// select again to avoid race condition if both are done
+// callBegin sets up the context cancellation of the next OCI call, callEnd must be called after the OCI call returns.
+// When the client supports it, a context deadline is set as the OCI call timeout.
+// The connection call watcher calls OCIBreak if the context is canceled before the OCI call returns,
+// or if the deadline passes and it is not the call timeout.
+func (conn *Conn) callBegin(ctx context.Context) {
+	if ctx.Done() == nil {
+		// context can not be canceled
+		return
+	}
+
+	if deadline, ok := ctx.Deadline(); ok && clientVersionMajor >= 18 {
+		timeout := time.Until(deadline) / time.Millisecond
+		if timeout < 1 {
+			timeout = 1
+		} else if timeout > math.MaxUint32 {
+			timeout = math.MaxUint32
+		}
+		callTimeout := C.ub4(timeout)
+		err := conn.ociAttrSet(unsafe.Pointer(conn.svc), C.OCI_HTYPE_SVCCTX, unsafe.Pointer(&callTimeout), 0, C.OCI_ATTR_CALL_TIMEOUT)
+		if err == nil {
+			conn.callTimeoutSet = true
+		} else {
+			conn.logger.Print("call timeout attribute set error: ", err)
+		}
+	}
+
+	if conn.callWatch == nil {
+		conn.callWatch = make(chan callWatchStruct)
+		conn.callDone = make(chan struct{})
+		go conn.callWatcher(conn.callWatch, conn.callDone)
+	}
+	conn.callWatch <- callWatchStruct{ctx: ctx, timeoutSet: conn.callTimeoutSet}
+	conn.callWatching = true
+}
+
+// callEnd clears the call timeout or stops the call watcher from watching the context.
+// If the call watcher called OCIBreak, calls OCIReset so the connection can be used again.
+func (conn *Conn) callEnd() {
+	if conn.callTimeoutSet {
+		conn.callTimeoutSet = false
+		var callTimeout C.ub4
+		err := conn.ociAttrSet(unsafe.Pointer(conn.svc), C.OCI_HTYPE_SVCCTX, unsafe.Pointer(&callTimeout), 0, C.OCI_ATTR_CALL_TIMEOUT)
+		if err != nil {
+			conn.logger.Print("call timeout attribute set error: ", err)
+		}
+	}
+
+	if conn.callWatching {
+		conn.callWatching = false
+		// the call watcher receives from callDone after setting callBroke, so callBroke can be read after the send
+		conn.callDone <- struct{}{}
+		if conn.callBroke {
+			conn.callBroke = false
+			conn.ociReset()
+		}
+	}
+}
+
+// callWatcher calls OCIBreak if a context from watch is done before done is received.
+// There is one call watcher for each connection, it exits when watch is closed.
+func (conn *Conn) callWatcher(watch chan callWatchStruct, done chan struct{}) {
+	for call := range watch {
 		select {
 		case <-done:
-		default:
+		case <-call.ctx.Done():
+			if call.timeoutSet && call.ctx.Err() == context.DeadlineExceeded {
+				// the OCI call timeout ends the call
+				<-done
+				continue
+			}
 			conn.ociBreak()
+			conn.callBroke = true
+			<-done
 		}
 	}
 }
@@ -606,3 +916,15 @@ func (conn *Conn) ociBreak() {
 		conn.logger.Print("OCIBreak error: ", err)
 	}
 }
+
+// ociReset calls OCIReset to reset the connection after an OCIBreak
+func (conn *Conn) ociReset() {
+	result := C.OCIReset(
+		unsafe.Pointer(conn.svc), // service or server context handle
+		conn.errHandle,           // error handle
+	)
+	err := conn.getError(result)
+	if err != nil {
+		conn.logger.Print("OCIReset error: ", err)
+	}
+}
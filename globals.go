@@ -11,11 +11,12 @@ import "C"
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"errors"
 	"io/ioutil"
 	"log"
+	"math/big"
 	"reflect"
-	"regexp"
 	"strconv"
 	"sync"
 	"time"
@@ -28,18 +29,51 @@ const (
 	sizeOfNilPointer   = unsafe.Sizeof(unsafe.Pointer(nil))
 )
 
+const (
+	// BatchErrors is an exec argument that enables batch error mode (OCI_BATCH_ERRORS) for an array DML.
+	// Rows that fail do not stop the remaining rows from being processed, the failures are returned in a *BatchError.
+	BatchErrors ArrayDMLOption = iota + 1
+	// PLSQLArrays is an exec argument that binds slices as PL/SQL associative arrays (index-by tables)
+	// instead of executing an array DML. A sql.Out with a pointer to a slice is an OUT or IN OUT associative array,
+	// the capacity of the slice is the max number of elements that can be returned.
+	PLSQLArrays
+)
+
+const (
+	// NumberTypeFloat is a query argument that returns the NUMBER columns of the query as float64 or int64, like number_type=float
+	NumberTypeFloat NumberType = iota + 1
+	// NumberTypeString is a query argument that returns the NUMBER columns of the query as exact decimal strings, like number_type=string
+	NumberTypeString
+	// NumberTypeJSON is a query argument that returns the NUMBER columns of the query as exact json.Number, like number_type=json
+	NumberTypeJSON
+	// NumberTypeBig is a query argument that returns the NUMBER columns of the query as *big.Int or *big.Rat, like number_type=big
+	NumberTypeBig
+)
+
 type (
 	// DSN is Oracle Data Source Name
 	DSN struct {
-		Connect              string
-		Username             string
-		Password             string
-		prefetchRows         C.ub4
-		prefetchMemory       C.ub4
-		timeLocation         *time.Location
-		transactionMode      C.ub4
-		enableQMPlaceholders bool
-		operationMode        C.ub4
+		Connect           string
+		Username          string
+		Password          string
+		prefetchRows      C.ub4
+		prefetchMemory    C.ub4
+		fetchArraySize    C.ub4
+		numberType        numberType
+		timeLocation      *time.Location
+		transactionMode   C.ub4
+		placeholderStyles placeholderStyle
+		operationMode     C.ub4
+		sessionPool       bool
+		poolMin           C.ub4
+		poolMax           C.ub4
+		poolIncrement     C.ub4
+		poolGetTimeout    time.Duration
+		poolIdleTimeout   time.Duration
+		connectionClass   string
+		purity            C.ub4
+		stmtCacheSize     C.ub4
+		resetPackage      bool
 	}
 
 	// DriverStruct is Oracle driver struct
@@ -57,40 +91,109 @@ type (
 
 	// Conn is Oracle connection
 	Conn struct {
-		svc                  *C.OCISvcCtx
-		srv                  *C.OCIServer
-		env                  *C.OCIEnv
-		errHandle            *C.OCIError
-		usrSession           *C.OCISession
-		prefetchRows         C.ub4
-		prefetchMemory       C.ub4
-		transactionMode      C.ub4
-		operationMode        C.ub4
-		inTransaction        bool
-		enableQMPlaceholders bool
-		closed               bool
-		timeLocation         *time.Location
-		logger               *log.Logger
+		svc               *C.OCISvcCtx
+		srv               *C.OCIServer
+		env               *C.OCIEnv
+		errHandle         *C.OCIError
+		usrSession        *C.OCISession
+		prefetchRows      C.ub4
+		prefetchMemory    C.ub4
+		fetchArraySize    C.ub4
+		numberType        numberType
+		transactionMode   C.ub4
+		operationMode     C.ub4
+		inTransaction     bool
+		tx                *Tx
+		placeholderStyles placeholderStyle
+		closed            bool
+		badConn           bool
+		timeLocation      *time.Location
+		logger            *log.Logger
+		sessionPool       *sessionPool
+		stmtCacheSize     C.ub4
+		stmtCacheHits     uint64
+		stmtCacheMisses   uint64
+		resetPackage      bool
+		callTimeoutSet    bool
+		callWatch         chan callWatchStruct
+		callDone          chan struct{}
+		callWatching      bool
+		callBroke         bool
+		objectTypes       map[string]*objectType
 	}
 
 	// Tx is Oracle transaction
 	Tx struct {
-		conn *Conn
+		conn        *Conn
+		savepoints  []string
+		xid         *XID
+		transHandle unsafe.Pointer
+		prepared    bool
+		detached    bool
+		completed   bool
+	}
+
+	// XID is a global transaction identifier of a two-phase commit transaction branch
+	XID struct {
+		// FormatID is the format identifier of the global transaction ID and branch qualifier
+		FormatID int32
+		// GlobalTransactionID is the global transaction ID (gtrid), 1 to 64 bytes
+		GlobalTransactionID []byte
+		// BranchQualifier is the branch qualifier (bqual), up to 64 bytes
+		BranchQualifier []byte
+	}
+
+	// LTXIDOutcome is the outcome of a logical transaction from GetLTXIDOutcome
+	LTXIDOutcome struct {
+		// Committed is true if the transaction committed
+		Committed bool
+		// UserCallCompleted is true if the call that committed the transaction completed,
+		// false if it may still have had more work to do after the commit, like a PL/SQL block
+		UserCallCompleted bool
+	}
+
+	// xidContextKey is the context key for xidContext
+	xidContextKey struct{}
+
+	// xidContext is the two-phase commit transaction branch BeginTx starts or resumes
+	xidContext struct {
+		xid     XID
+		timeout time.Duration
+		resume  bool
 	}
 
 	// Stmt is Oracle statement
 	Stmt struct {
-		conn   *Conn
-		stmt   *C.OCIStmt
-		closed bool
-		ctx    context.Context
+		conn         *Conn
+		stmt         *C.OCIStmt
+		closed       bool
+		ctx          context.Context
+		batchErrors  bool
+		dmlRowCounts *DMLRowCounts
+		plsqlArrays  bool
+		cacheKey     string
+		// numberType is the NumberType query argument, 0 uses the connection number type
+		numberType NumberType
+		// ordinalBinds is the number of arguments when $N placeholders are bound by name with the argument ordinal
+		ordinalBinds int
 	}
 
 	// Rows is Oracle rows
 	Rows struct {
-		stmt    *Stmt
-		defines []defineStruct
-		closed  bool
+		stmt           *Stmt
+		defines        []defineStruct
+		closed         bool
+		fetchArraySize int
+		rowsFetched    int
+		rowIndex       int
+		fetchDone      bool
+		// freeCursor is true if the statement handle is a ref cursor out bind to free on close
+		freeCursor bool
+		// implicitStmt is the PL/SQL statement that returned implicit result sets
+		implicitStmt    *Stmt
+		implicitResults int
+		// numberType is the NumberType of the query, for ref cursor columns and implicit results
+		numberType NumberType
 	}
 
 	// Result is Oracle result
@@ -102,6 +205,216 @@ type (
 		stmt            *Stmt
 	}
 
+	// SessionPoolStats are the statistics of an OCI session pool
+	SessionPoolStats struct {
+		// Open is the number of sessions open in the pool
+		Open int
+		// Busy is the number of sessions in use
+		Busy int
+		// Min is the min number of sessions in the pool
+		Min int
+		// Max is the max number of sessions in the pool
+		Max int
+		// Increment is the number of sessions opened when more sessions are needed
+		Increment int
+	}
+
+	// StmtCacheStats are the statistics of the OCI statement cache of a connection
+	StmtCacheStats struct {
+		// Size is the max number of statements in the statement cache, 0 when the statement cache is disabled
+		Size int
+		// Hits is the number of prepares that found the statement in the statement cache
+		Hits uint64
+		// Misses is the number of prepares that did not find the statement in the statement cache
+		Misses uint64
+	}
+
+	// sessionPool is an OCI session pool shared by all the connections with the same DSN pool key.
+	// The pool is kept while no connections use it, until the pool idle timeout or CloseSessionPool.
+	sessionPool struct {
+		env        *C.OCIEnv
+		errHandle  *C.OCIError
+		pool       *C.OCISPool
+		name       *C.OraText
+		nameLength C.ub4
+		mutex      sync.Mutex // protects errHandle
+		key        string
+		// references is the number of connections using the pool, protected by sessionPoolsMutex
+		references  int
+		idleTimeout time.Duration
+		// destroyTimer destroys the pool after the idle timeout without references, protected by sessionPoolsMutex
+		destroyTimer *time.Timer
+	}
+
+	// ArrayDMLOption is an exec argument that changes how an array DML is executed.
+	// It is removed from the arguments and is not bound.
+	ArrayDMLOption int
+
+	// NumberType is a query argument that sets the Go type the NUMBER columns of the query are returned as,
+	// instead of the number_type DSN parameter. It is removed from the arguments and is not bound.
+	NumberType int
+
+	// Object is an instance of an Oracle object type.
+	// Object columns are returned as Object values, and an Object can be bound as an IN parameter
+	// or a *Object as an OUT or IN OUT parameter.
+	Object struct {
+		// TypeName is the object type name, like SCHEMA.TYPE_NAME. It is needed to bind an Object.
+		TypeName string
+		// Attributes are the attribute values by attribute name, nested objects are Object values.
+		// Nil Attributes is a null object.
+		Attributes map[string]interface{}
+	}
+
+	// ObjectStruct converts between an Oracle object type instance and a Go struct.
+	// Struct fields are matched to attributes by the oci8 struct tag, otherwise by the upper case field name.
+	// Fields with the tag "-" are skipped. Nested struct fields are nested objects.
+	// A *ObjectStruct is a scan destination, an ObjectStruct is an IN parameter, and a *ObjectStruct is an OUT or IN OUT parameter.
+	ObjectStruct struct {
+		// TypeName is the object type name, like SCHEMA.TYPE_NAME. It is needed to bind an ObjectStruct.
+		TypeName string
+		// Struct is a pointer to a struct, or a pointer to a pointer to a struct that is set to nil for a null object
+		Struct interface{}
+	}
+
+	// Collection is an instance of an Oracle collection type, a VARRAY or a nested table.
+	// Collection columns are returned as Collection values, and a Collection can be bound as an IN parameter
+	// or a *Collection as an OUT or IN OUT parameter.
+	Collection struct {
+		// TypeName is the collection type name, like SCHEMA.TYPE_NAME. It is needed to bind a Collection.
+		TypeName string
+		// Elements are the element values, nested objects are Object values and nested collections are Collection values.
+		// Nil Elements is a null collection.
+		Elements []interface{}
+	}
+
+	// CollectionSlice converts between an Oracle collection type instance and a Go slice.
+	// Elements are converted like ObjectStruct fields, so a slice of structs is a collection of objects.
+	// A *CollectionSlice is a scan destination, a CollectionSlice is an IN parameter, and a *CollectionSlice is an OUT or IN OUT parameter.
+	CollectionSlice struct {
+		// TypeName is the collection type name, like SCHEMA.TYPE_NAME. It is needed to bind a CollectionSlice.
+		TypeName string
+		// Slice is a pointer to a slice, that is set to nil for a null collection.
+		// An IN parameter can also be the slice itself.
+		Slice interface{}
+	}
+
+	// Boolean binds a native BOOLEAN, which needs Oracle 12.1 or later for PL/SQL and Oracle 23 or later for SQL.
+	// A bool, and a sql.Out of a *bool or *sql.NullBool, is bound as a 0/1 integer on all versions,
+	// because before Oracle 23 PL/SQL does not convert between BOOLEAN and NUMBER, so existing NUMBER parameters keep working.
+	// A Boolean is needed for PL/SQL BOOLEAN parameters and variables:
+	// a Boolean is an IN parameter and a *Boolean is an OUT or IN OUT parameter.
+	// BOOLEAN columns and PL/SQL results are fetched as bool.
+	Boolean struct {
+		Bool bool
+		// Valid is false for a null
+		Valid bool
+	}
+
+	// JSON is the value of an Oracle JSON column, fetched and bound in the OSON binary format.
+	// JSON columns are returned as JSON values, and a *JSON is a scan destination.
+	// Go maps with string keys and structs are bound to JSON columns as JSON values.
+	// A json.RawMessage is bound as []byte, so wrap it in a JSON to bind it as a JSON value.
+	JSON struct {
+		// Value is the decoded value: map[string]interface{} for objects, []interface{} for arrays,
+		// string, bool, json.Number for exact numbers, float64 for binary floats and doubles,
+		// time.Time for dates and timestamps, time.Duration for day to second intervals, []byte for binary values, or nil.
+		// When scanning, a non-nil pointer Value, like a *json.RawMessage or a pointer to a struct, is set with encoding/json.
+		Value interface{}
+	}
+
+	// Vector is an Oracle 23 dense VECTOR value to bind, a *Vector is an OUT or IN OUT parameter.
+	// Dense VECTOR columns are returned as []float32, []float64, []int8, or []byte for the BINARY format.
+	Vector struct {
+		// Values is []float32, []float64, []int8, or []byte for the BINARY format with 8 dimensions in each byte.
+		// Nil Values is a null vector.
+		Values interface{}
+	}
+
+	// SparseVector is an Oracle 23 sparse VECTOR value. Sparse VECTOR columns are returned as SparseVector values.
+	// A SparseVector is an IN parameter and a *SparseVector is an OUT or IN OUT parameter.
+	SparseVector struct {
+		// Dimensions is the number of dimensions of the vector
+		Dimensions int
+		// Indices are the indices of the non-zero values
+		Indices []uint32
+		// Values are the non-zero values, []float32, []float64, or []int8. Nil Values is a null vector.
+		Values interface{}
+	}
+
+	// XMLType is an Oracle XMLType document.
+	// XMLType columns are fetched through a CLOB and returned as strings, a *XMLType is also a scan destination.
+	// An XMLType is bound as a temporary CLOB, that SQL converts to XMLType.
+	XMLType struct {
+		// Document is the XML document, a string or []byte. Nil Document is a null XMLType.
+		Document interface{}
+	}
+
+	// objectType is a described Oracle object type or collection type
+	objectType struct {
+		conn *Conn
+		name string
+		tdo  *C.OCIType
+		// typeCode is OCI_TYPECODE_OBJECT or OCI_TYPECODE_NAMEDCOLLECTION
+		typeCode   C.OCITypeCode
+		attributes []objectAttribute
+		// element is the element of a collection type
+		element *objectAttribute
+	}
+
+	// objectAttribute is an attribute of an Oracle object type, or the element of a collection type
+	objectAttribute struct {
+		name       string
+		typeCode   C.OCITypeCode
+		precision  C.sb2
+		scale      C.sb1
+		objectType *objectType
+	}
+
+	// objectInstance is an object instance pointer and null indicator struct pointer of a define or bind.
+	// The pointers are in C memory so OCI can set them.
+	objectInstance struct {
+		objectType *objectType
+		pointers   unsafe.Pointer
+	}
+
+	// DMLRowCounts is an exec argument that receives the number of rows affected by each iteration of an array DML.
+	// Pass a pointer to it as an exec argument, it is removed from the arguments and is not bound.
+	DMLRowCounts []int64
+
+	// BatchError is returned from an array DML executed with the BatchErrors option when some of the rows failed.
+	// The rows without errors have been processed.
+	BatchError struct {
+		RowsAffected int64
+		Errors       []BatchRowError
+	}
+
+	// OraError is an Oracle error from an OCI call.
+	// Use errors.Is with ErrUniqueViolation, ErrDeadlock, ErrSerializationFailure, ErrResourceBusy,
+	// ErrValueTooLarge, or driver.ErrBadConn to check the kind of error.
+	OraError struct {
+		// Code is the ORA error code of the first error record
+		Code int
+		// Message is the error message of the first error record
+		Message string
+		// Offset is the parse error offset into the statement text, only set for errors from executing a statement
+		Offset int
+		// Records are all the error records, the first one has the same Code and Message as the OraError
+		Records []OraErrorRecord
+	}
+
+	// OraErrorRecord is a single error record of an OraError
+	OraErrorRecord struct {
+		Code    int
+		Message string
+	}
+
+	// BatchRowError is an error for a single row of an array DML
+	BatchRowError struct {
+		Offset  int
+		Code    int
+		Message string
+	}
+
 	defineStruct struct {
 		name         string
 		dataType     C.ub2
@@ -111,16 +424,38 @@ type (
 		indicator    *C.sb2
 		defineHandle *C.OCIDefine
 		subDefines   []defineStruct
+		arraySize    int
+		numberType   numberType
+		object       *objectInstance
+		vectorFormat C.ub1
+		vectorSparse bool
+	}
+
+	// callWatchStruct is a context for the call watcher to watch
+	callWatchStruct struct {
+		ctx context.Context
+		// timeoutSet is true when the context deadline is the OCI call timeout, then only a cancel calls OCIBreak
+		timeoutSet bool
 	}
 
 	bindStruct struct {
-		dataType   C.ub2
-		pbuf       unsafe.Pointer
-		maxSize    C.sb4
-		length     *C.ub2
-		indicator  *C.sb2
-		bindHandle *C.OCIBind
-		out        sql.Out
+		dataType    C.ub2
+		pbuf        unsafe.Pointer
+		maxSize     C.sb4
+		length      *C.ub2
+		indicator   *C.sb2
+		bindHandle  *C.OCIBind
+		out         sql.Out
+		isArray     bool
+		arrayLength int
+		returning   *C.oci8ReturningBind
+		// plsqlArrayMax is the max number of elements of a PL/SQL associative array bind
+		plsqlArrayMax C.ub4
+		// plsqlArrayCurrent is the current number of elements of a PL/SQL associative array bind
+		plsqlArrayCurrent *C.ub4
+		object            *objectInstance
+		// temporaryLobConn is the connection of the temporary LOB in pbuf, which is freed with the bind
+		temporaryLobConn *Conn
 	}
 )
 
@@ -141,15 +476,41 @@ var (
 	// ErrNoRowid is result has no rowid
 	ErrNoRowid = errors.New("result has no rowid")
 
-	phre           = regexp.MustCompile(`\?`)
+	// ErrUniqueViolation is an OraError classification for ORA-00001
+	ErrUniqueViolation = errors.New("unique constraint violated")
+	// ErrDeadlock is an OraError classification for ORA-00060
+	ErrDeadlock = errors.New("deadlock detected")
+	// ErrSerializationFailure is an OraError classification for ORA-08177
+	ErrSerializationFailure = errors.New("can not serialize access for this transaction")
+	// ErrResourceBusy is an OraError classification for ORA-00054 and ORA-30006
+	ErrResourceBusy = errors.New("resource busy")
+	// ErrValueTooLarge is an OraError classification for ORA-01401, ORA-01438, and ORA-12899
+	ErrValueTooLarge = errors.New("value too large")
+
 	defaultCharset = C.ub2(0)
 
-	typeNil       = reflect.TypeOf(nil)
-	typeString    = reflect.TypeOf("a")
-	typeSliceByte = reflect.TypeOf([]byte{})
-	typeInt64     = reflect.TypeOf(int64(1))
-	typeFloat64   = reflect.TypeOf(float64(1))
-	typeTime      = reflect.TypeOf(time.Time{})
+	// clientVersionMajor is the Oracle Client major version
+	clientVersionMajor int
+
+	typeNil          = reflect.TypeOf(nil)
+	typeString       = reflect.TypeOf("a")
+	typeSliceByte    = reflect.TypeOf([]byte{})
+	typeBool         = reflect.TypeOf(false)
+	typeInt64        = reflect.TypeOf(int64(1))
+	typeFloat64      = reflect.TypeOf(float64(1))
+	typeTime         = reflect.TypeOf(time.Time{})
+	typeJSONNumber   = reflect.TypeOf(json.Number(""))
+	typeBigInt       = reflect.TypeOf(&big.Int{})
+	typeBigRat       = reflect.TypeOf(&big.Rat{})
+	typeObject       = reflect.TypeOf(Object{})
+	typeCollection   = reflect.TypeOf(Collection{})
+	typeJSON         = reflect.TypeOf(JSON{})
+	typeSliceFloat32 = reflect.TypeOf([]float32{})
+	typeSliceFloat64 = reflect.TypeOf([]float64{})
+	typeSliceInt8    = reflect.TypeOf([]int8{})
+	typeVector       = reflect.TypeOf(Vector{})
+	typeSparseVector = reflect.TypeOf(SparseVector{})
+	typeInterface    = reflect.TypeOf((*interface{})(nil)).Elem()
 
 	// Driver is the sql driver
 	Driver = &DriverStruct{
@@ -158,6 +519,9 @@ var (
 
 	timeLocations []*time.Location
 
+	sessionPools      = make(map[string]*sessionPool)
+	sessionPoolsMutex sync.Mutex
+
 	byteBufferPool = sync.Pool{
 		New: func() interface{} {
 			return make([]byte, lobBufferSize)
@@ -181,6 +545,10 @@ func init() {
 	C.free(unsafe.Pointer(nlsLang))
 	C.OCIHandleFree(unsafe.Pointer(*envPP), C.OCI_HTYPE_ENV)
 
+	var major, minor, update, patch, portUpdate C.sword
+	C.OCIClientVersion(&major, &minor, &update, &patch, &portUpdate)
+	clientVersionMajor = int(major)
+
 	// build timeLocations: GMT -12 to 14
 	timeLocationNames := []string{"Etc/GMT+12", "Pacific/Pago_Pago", // -12 to -11
 		"Pacific/Honolulu", "Pacific/Gambier", "Pacific/Pitcairn", "America/Phoenix", "America/Costa_Rica", // -10 to -6
@@ -0,0 +1,122 @@
+package oci8
+
+// #include "oci8.go.h"
+import "C"
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"unsafe"
+)
+
+// jsonBufferSize is the starting buffer size to get the OSON image of a JSON descriptor
+const jsonBufferSize = 32768
+
+// Scan implements sql.Scanner.
+// When Value is a non-nil pointer it is set with encoding/json, otherwise Value is set to the decoded value.
+func (aJSON *JSON) Scan(src interface{}) error {
+	switch value := src.(type) {
+	case nil:
+	case JSON:
+		src = value.Value
+	default:
+		return fmt.Errorf("can not scan %T into JSON", src)
+	}
+
+	rv := reflect.ValueOf(aJSON.Value)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		aJSON.Value = src
+		return nil
+	}
+
+	data, err := json.Marshal(src)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, aJSON.Value)
+}
+
+// isJSONValue returns true if value is bound as JSON: a map with string keys or a struct that is not a driver type
+func isJSONValue(value interface{}) bool {
+	rt := reflect.TypeOf(value)
+	if rt == nil {
+		return false
+	}
+	if rt.Kind() == reflect.Map {
+		return rt.Key().Kind() == reflect.String
+	}
+	return isObjectStruct(rt)
+}
+
+// makeJSONBind encodes the value as OSON and loads it into a JSON descriptor.
+// A nil Value binds a null.
+func (stmt *Stmt) makeJSONBind(sbind *bindStruct, value JSON) error {
+	jsonP, _, err := stmt.conn.ociDescriptorAlloc(C.OCI_DTYPE_JSON, 0)
+	if err != nil {
+		return err
+	}
+	sbind.dataType = C.SQLT_JSON
+	sbind.pbuf = unsafe.Pointer(jsonP)
+	sbind.maxSize = C.sb4(sizeOfNilPointer)
+	*sbind.length = C.ub2(sizeOfNilPointer)
+
+	if value.Value == nil {
+		*sbind.indicator = -1 // set to null
+		return nil
+	}
+
+	image, err := encodeOSON(value.Value, stmt.conn.timeLocation)
+	if err != nil {
+		return err
+	}
+
+	buffer := C.CBytes(image)
+	defer C.free(buffer)
+	result := C.OCIJsonBinaryBufferLoad(
+		stmt.conn.svc,        // service context handle
+		(*C.OCIJson)(*jsonP), // JSON descriptor
+		buffer,               // the OSON image
+		C.oraub8(len(image)), // size of the OSON image
+		C.OCI_DEFAULT,        // mode
+		stmt.conn.errHandle,  // error handle
+	)
+	return stmt.conn.getError(result)
+}
+
+// ociJSONValue gets the OSON image of a JSON descriptor then returns the decoded JSON value
+func (conn *Conn) ociJSONValue(jsonDescriptor *C.OCIJson) (JSON, error) {
+	size := C.oraub8(jsonBufferSize)
+	buffer := C.malloc(C.size_t(size))
+	defer func() {
+		C.free(buffer)
+	}()
+
+	for {
+		length := size
+		result := C.OCIJsonToBinaryBuffer(
+			conn.svc,       // service context handle
+			jsonDescriptor, // JSON descriptor
+			buffer,         // buffer for the OSON image
+			&length,        // size of the buffer, returns size of the OSON image
+			C.OCI_DEFAULT,  // mode
+			conn.errHandle, // error handle
+		)
+		err := conn.getError(result)
+		if err != nil {
+			return JSON{}, err
+		}
+		if length <= size {
+			value, err := decodeOSON(C.GoBytes(buffer, C.int(length)), conn.timeLocation)
+			if err != nil {
+				return JSON{}, err
+			}
+			return JSON{Value: value}, nil
+		}
+
+		// buffer is too small, retry with the size of the OSON image
+		C.free(buffer)
+		size = length
+		buffer = C.malloc(C.size_t(size))
+	}
+}
@@ -0,0 +1,352 @@
+package oci8
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// numberType is the Go type Oracle NUMBER columns are returned as
+type numberType int
+
+const (
+	// numberTypeFloat returns NUMBER as float64 or int64, decided by the column precision and scale
+	numberTypeFloat numberType = iota
+	// numberTypeString returns NUMBER as an exact decimal string
+	numberTypeString
+	// numberTypeJSON returns NUMBER as an exact json.Number
+	numberTypeJSON
+	// numberTypeBig returns NUMBER as *big.Int or *big.Rat, decided by the column precision and scale
+	numberTypeBig
+	// numberTypeBigInt returns NUMBER as *big.Int
+	numberTypeBigInt
+	// numberTypeBigRat returns NUMBER as *big.Rat
+	numberTypeBigRat
+)
+
+// numberType returns the number type of the NumberType query argument
+func (aNumberType NumberType) numberType() (numberType, error) {
+	switch aNumberType {
+	case NumberTypeFloat:
+		return numberTypeFloat, nil
+	case NumberTypeString:
+		return numberTypeString, nil
+	case NumberTypeJSON:
+		return numberTypeJSON, nil
+	case NumberTypeBig:
+		return numberTypeBig, nil
+	}
+	return 0, fmt.Errorf("unknown number type %d", aNumberType)
+}
+
+const (
+	// numberMaxMantissa is the max number of base 100 digits in an Oracle NUMBER
+	numberMaxMantissa = 20
+	// numberMaxLength is the max number of bytes in an Oracle NUMBER
+	numberMaxLength = 22
+)
+
+var (
+	errNumberInfinity = errors.New("number is infinity")
+)
+
+// decodeNumber converts the Oracle NUMBER format to a decimal string.
+//
+// The first byte is the sign and the base 100 exponent.
+// The following bytes are base 100 digits of the mantissa, plus 1 for positive numbers
+// and 101 minus the digit for negative numbers. Negative numbers end with 102 if there is room.
+// https://docs.oracle.com/en/database/oracle/oracle-database/12.2/lnoci/data-types.html#GUID-75F9D2B3-A8A9-4CB5-9A8E-7E3C6C6D4E0B
+func decodeNumber(b []byte) (string, error) {
+	if len(b) < 1 {
+		return "", errors.New("number has no data")
+	}
+
+	exponentByte := b[0]
+	switch {
+	case exponentByte == 0x80 && len(b) == 1:
+		return "0", nil
+	case exponentByte == 0 && len(b) == 1, exponentByte == 0xff && len(b) == 2 && b[1] == 101:
+		return "", errNumberInfinity
+	}
+
+	positive := exponentByte&0x80 != 0
+	var exponent int
+	mantissa := b[1:]
+	if positive {
+		exponent = int(exponentByte) - 193
+	} else {
+		exponent = 62 - int(exponentByte)
+		if len(mantissa) > 0 && mantissa[len(mantissa)-1] == 102 {
+			mantissa = mantissa[:len(mantissa)-1]
+		}
+	}
+	if len(mantissa) < 1 || len(mantissa) > numberMaxMantissa {
+		return "", fmt.Errorf("number has invalid mantissa length %v", len(mantissa))
+	}
+
+	// base 100 digits to decimal digits
+	digits := make([]byte, 0, len(mantissa)*2)
+	for _, digit := range mantissa {
+		var value int
+		if positive {
+			value = int(digit) - 1
+		} else {
+			value = 101 - int(digit)
+		}
+		if value < 0 || value > 99 {
+			return "", fmt.Errorf("number has invalid digit %v", digit)
+		}
+		digits = append(digits, byte('0'+value/10), byte('0'+value%10))
+	}
+
+	// the decimal point is after exponent + 1 base 100 digits
+	point := (exponent + 1) * 2
+
+	var integer string
+	var fraction string
+	switch {
+	case point <= 0:
+		integer = "0"
+		fraction = strings.Repeat("0", -point) + string(digits)
+	case point >= len(digits):
+		integer = string(digits) + strings.Repeat("0", point-len(digits))
+	default:
+		integer = string(digits[:point])
+		fraction = string(digits[point:])
+	}
+
+	integer = strings.TrimLeft(integer, "0")
+	if integer == "" {
+		integer = "0"
+	}
+	fraction = strings.TrimRight(fraction, "0")
+
+	var buffer strings.Builder
+	if !positive {
+		buffer.WriteByte('-')
+	}
+	buffer.WriteString(integer)
+	if fraction != "" {
+		buffer.WriteByte('.')
+		buffer.WriteString(fraction)
+	}
+
+	return buffer.String(), nil
+}
+
+// encodeNumber converts a decimal string to the Oracle NUMBER format.
+// The decimal string can have a sign, a decimal point, and an exponent, like -1.25e3
+func encodeNumber(s string) ([]byte, error) {
+	number := s
+	if number == "" {
+		return nil, errors.New("number is empty")
+	}
+
+	positive := true
+	switch number[0] {
+	case '-':
+		positive = false
+		number = number[1:]
+	case '+':
+		number = number[1:]
+	}
+
+	// exponent
+	exponent := 0
+	if i := strings.IndexAny(number, "eE"); i >= 0 {
+		var err error
+		exponent, err = parseNumberExponent(number[i+1:])
+		if err != nil {
+			return nil, fmt.Errorf("invalid number %q: %v", s, err)
+		}
+		number = number[:i]
+	}
+
+	// decimal digits and decimal point
+	digits := make([]byte, 0, len(number))
+	point := -1
+	for i := 0; i < len(number); i++ {
+		switch c := number[i]; {
+		case c >= '0' && c <= '9':
+			digits = append(digits, c-'0')
+		case c == '.' && point == -1:
+			point = len(digits)
+		default:
+			return nil, fmt.Errorf("invalid number %q", s)
+		}
+	}
+	if len(digits) < 1 {
+		return nil, fmt.Errorf("invalid number %q", s)
+	}
+	if point == -1 {
+		point = len(digits)
+	}
+	point += exponent
+
+	// remove leading and trailing zeros
+	for len(digits) > 0 && digits[0] == 0 {
+		digits = digits[1:]
+		point--
+	}
+	for len(digits) > 0 && digits[len(digits)-1] == 0 {
+		digits = digits[:len(digits)-1]
+	}
+	if len(digits) < 1 {
+		return []byte{0x80}, nil
+	}
+
+	// align the decimal point to base 100 digits
+	if point%2 != 0 {
+		digits = append([]byte{0}, digits...)
+		point++
+	}
+	if len(digits)%2 != 0 {
+		digits = append(digits, 0)
+	}
+	if len(digits)/2 > numberMaxMantissa {
+		return nil, fmt.Errorf("number %q has more than %v significant digits", s, numberMaxMantissa*2)
+	}
+
+	exponent = point/2 - 1
+	if exponent > 62 || exponent < -65 {
+		return nil, fmt.Errorf("number %q is out of range", s)
+	}
+
+	b := make([]byte, 1, numberMaxLength)
+	if positive {
+		b[0] = byte(exponent + 193)
+	} else {
+		b[0] = byte(62 - exponent)
+	}
+	for i := 0; i < len(digits); i += 2 {
+		value := digits[i]*10 + digits[i+1]
+		if positive {
+			b = append(b, value+1)
+		} else {
+			b = append(b, 101-value)
+		}
+	}
+	if !positive && len(b) <= numberMaxMantissa {
+		b = append(b, 102)
+	}
+
+	return b, nil
+}
+
+// parseNumberExponent parses the exponent of a decimal string
+func parseNumberExponent(s string) (int, error) {
+	if s == "" {
+		return 0, errors.New("empty exponent")
+	}
+	negative := false
+	switch s[0] {
+	case '-':
+		negative = true
+		s = s[1:]
+	case '+':
+		s = s[1:]
+	}
+	if s == "" || len(s) > 4 {
+		return 0, errors.New("invalid exponent")
+	}
+	exponent := 0
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return 0, errors.New("invalid exponent")
+		}
+		exponent = exponent*10 + int(s[i]-'0')
+	}
+	if negative {
+		return -exponent, nil
+	}
+	return exponent, nil
+}
+
+// ratToDecimalString converts a big.Rat to an exact decimal string.
+// Returns an error if the big.Rat does not have a finite decimal representation, like 1/3
+func ratToDecimalString(rat *big.Rat) (string, error) {
+	if rat.IsInt() {
+		return rat.Num().String(), nil
+	}
+
+	// a fraction has a finite decimal representation if the denominator only has factors of 2 and 5
+	denominator := new(big.Int).Set(rat.Denom())
+	two := big.NewInt(2)
+	five := big.NewInt(5)
+	remainder := new(big.Int)
+	var twos, fives int
+	for {
+		quotient, mod := new(big.Int).QuoRem(denominator, two, remainder)
+		if mod.Sign() != 0 {
+			break
+		}
+		denominator = quotient
+		twos++
+	}
+	for {
+		quotient, mod := new(big.Int).QuoRem(denominator, five, remainder)
+		if mod.Sign() != 0 {
+			break
+		}
+		denominator = quotient
+		fives++
+	}
+	if denominator.Cmp(big.NewInt(1)) != 0 {
+		return "", fmt.Errorf("number %v does not have a finite decimal representation", rat.String())
+	}
+
+	precision := twos
+	if fives > precision {
+		precision = fives
+	}
+	return rat.FloatString(precision), nil
+}
+
+// numberToDecimalString converts a json.Number, *big.Int, or *big.Rat to a decimal string.
+// A nil *big.Int or *big.Rat returns an empty string.
+func numberToDecimalString(value interface{}) (string, error) {
+	switch number := value.(type) {
+	case json.Number:
+		return string(number), nil
+	case *big.Int:
+		if number == nil {
+			return "", nil
+		}
+		return number.String(), nil
+	case *big.Rat:
+		if number == nil {
+			return "", nil
+		}
+		return ratToDecimalString(number)
+	}
+	return "", fmt.Errorf("unsupported number type %T", value)
+}
+
+// decodeNumberAs converts the Oracle NUMBER format to the Go type for numberType
+func decodeNumberAs(b []byte, aNumberType numberType) (interface{}, error) {
+	number, err := decodeNumber(b)
+	if err != nil {
+		return nil, err
+	}
+
+	switch aNumberType {
+	case numberTypeJSON:
+		return json.Number(number), nil
+	case numberTypeBigInt:
+		bigInt, ok := new(big.Int).SetString(number, 10)
+		if !ok {
+			return nil, fmt.Errorf("number %v is not an integer", number)
+		}
+		return bigInt, nil
+	case numberTypeBigRat:
+		bigRat, ok := new(big.Rat).SetString(number)
+		if !ok {
+			return nil, fmt.Errorf("number %v is not a rational", number)
+		}
+		return bigRat, nil
+	}
+
+	return number, nil
+}
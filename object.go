@@ -0,0 +1,1032 @@
+package oci8
+
+// #include "oci8.go.h"
+import "C"
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+	"unsafe"
+)
+
+var (
+	typeScanner = reflect.TypeOf((*sql.Scanner)(nil)).Elem()
+	typeValuer  = reflect.TypeOf((*driver.Valuer)(nil)).Elem()
+)
+
+// Scan implements sql.Scanner, a null object sets Attributes to nil
+func (object *Object) Scan(src interface{}) error {
+	switch value := src.(type) {
+	case nil:
+		object.Attributes = nil
+	case Object:
+		*object = value
+	default:
+		return fmt.Errorf("can not scan %T into Object", src)
+	}
+	return nil
+}
+
+// Scan implements sql.Scanner, setting the struct fields from the object attributes
+func (objectStruct *ObjectStruct) Scan(src interface{}) error {
+	rv := reflect.ValueOf(objectStruct.Struct)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return fmt.Errorf("ObjectStruct Struct is %T, expected a pointer to a struct", objectStruct.Struct)
+	}
+
+	switch value := src.(type) {
+	case nil:
+		return setStructFromObject(rv.Elem(), nil)
+	case Object:
+		return setStructFromObject(rv.Elem(), &value)
+	}
+	return fmt.Errorf("can not scan %T into ObjectStruct", src)
+}
+
+// object returns an Object with the attributes set from the struct fields
+func (objectStruct *ObjectStruct) object() (*Object, error) {
+	if objectStruct.Struct == nil {
+		return &Object{TypeName: objectStruct.TypeName}, nil
+	}
+	return objectFromStruct(objectStruct.TypeName, reflect.ValueOf(objectStruct.Struct))
+}
+
+// structAttributeName returns the attribute name of a struct field, or empty string if the field is skipped
+func structAttributeName(field reflect.StructField) string {
+	if field.PkgPath != "" {
+		// unexported
+		return ""
+	}
+	tag := field.Tag.Get("oci8")
+	if tag == "-" {
+		return ""
+	}
+	if tag != "" {
+		return tag
+	}
+	return strings.ToUpper(field.Name)
+}
+
+// isObjectStruct returns true if values of the type are converted to and from nested objects.
+// Structs that are values on their own, like time.Time and sql.NullString, are not.
+func isObjectStruct(rt reflect.Type) bool {
+	for rt.Kind() == reflect.Ptr {
+		rt = rt.Elem()
+	}
+	if rt.Kind() != reflect.Struct || rt == typeTime || rt == typeObject || rt == typeCollection || rt == typeJSON ||
+		rt == typeVector || rt == typeSparseVector || rt == typeBigInt.Elem() || rt == typeBigRat.Elem() {
+		return false
+	}
+	return !reflect.PtrTo(rt).Implements(typeScanner) && !rt.Implements(typeValuer)
+}
+
+// objectFromStruct returns an Object with the attributes set from the fields of a struct or pointer to a struct.
+// A nil pointer returns a null Object.
+func objectFromStruct(typeName string, rv reflect.Value) (*Object, error) {
+	for rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return &Object{TypeName: typeName}, nil
+		}
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("%v is not a struct", rv.Type())
+	}
+
+	object := &Object{TypeName: typeName, Attributes: make(map[string]interface{}, rv.NumField())}
+	for i := 0; i < rv.NumField(); i++ {
+		name := structAttributeName(rv.Type().Field(i))
+		if name == "" {
+			continue
+		}
+		field := rv.Field(i)
+		if isObjectStruct(field.Type()) {
+			nested, err := objectFromStruct("", field)
+			if err != nil {
+				return nil, fmt.Errorf("field %v - error: %v", rv.Type().Field(i).Name, err)
+			}
+			object.Attributes[name] = *nested
+			continue
+		}
+		if isCollectionSlice(field.Type()) {
+			nested, err := collectionFromSlice("", field)
+			if err != nil {
+				return nil, fmt.Errorf("field %v - error: %v", rv.Type().Field(i).Name, err)
+			}
+			object.Attributes[name] = *nested
+			continue
+		}
+		object.Attributes[name] = field.Interface()
+	}
+
+	return object, nil
+}
+
+// setStructFromObject sets a struct, or pointer to a struct, to the attributes of an object.
+// A null object sets a pointer to nil or a struct to the zero value.
+func setStructFromObject(dest reflect.Value, object *Object) error {
+	if object == nil || object.Attributes == nil {
+		dest.Set(reflect.Zero(dest.Type()))
+		return nil
+	}
+	if dest.Kind() == reflect.Ptr {
+		if dest.IsNil() {
+			dest.Set(reflect.New(dest.Type().Elem()))
+		}
+		return setStructFromObject(dest.Elem(), object)
+	}
+	if dest.Kind() != reflect.Struct {
+		return fmt.Errorf("%v is not a struct", dest.Type())
+	}
+
+	for i := 0; i < dest.NumField(); i++ {
+		name := structAttributeName(dest.Type().Field(i))
+		if name == "" {
+			continue
+		}
+		value, ok := object.Attributes[name]
+		if !ok {
+			continue
+		}
+		err := setFieldValue(dest.Field(i), value)
+		if err != nil {
+			return fmt.Errorf("attribute %v - error: %v", name, err)
+		}
+	}
+
+	return nil
+}
+
+// setFieldValue sets a struct field, or slice element, to an attribute or collection element value
+func setFieldValue(field reflect.Value, value interface{}) error {
+	if nested, ok := value.(Object); ok && isObjectStruct(field.Type()) {
+		return setStructFromObject(field, &nested)
+	}
+	if nested, ok := value.(Collection); ok && isCollectionSlice(field.Type()) {
+		return setSliceFromCollection(field, &nested)
+	}
+	if scanner, ok := field.Addr().Interface().(sql.Scanner); ok {
+		return scanner.Scan(value)
+	}
+	if value == nil {
+		field.Set(reflect.Zero(field.Type()))
+		return nil
+	}
+	if field.Kind() == reflect.Ptr {
+		pointer := reflect.New(field.Type().Elem())
+		err := setFieldValue(pointer.Elem(), value)
+		if err != nil {
+			return err
+		}
+		field.Set(pointer)
+		return nil
+	}
+
+	rv := reflect.ValueOf(value)
+	if rv.Type().AssignableTo(field.Type()) {
+		field.Set(rv)
+		return nil
+	}
+	// number to string conversions are not done, Go would convert them as runes
+	if rv.Type().ConvertibleTo(field.Type()) && isNumberKind(rv.Kind()) == isNumberKind(field.Kind()) {
+		field.Set(rv.Convert(field.Type()))
+		return nil
+	}
+
+	return fmt.Errorf("can not set %T to %v", value, field.Type())
+}
+
+// isNumberKind returns true if kind is an integer or float kind
+func isNumberKind(kind reflect.Kind) bool {
+	return kind >= reflect.Int && kind <= reflect.Float64
+}
+
+// objectTypeByName returns the object type for the type name, like SCHEMA.TYPE_NAME.
+// Object types are cached by the connection.
+func (conn *Conn) objectTypeByName(name string) (*objectType, error) {
+	if objType, ok := conn.objectTypes[name]; ok {
+		return objType, nil
+	}
+
+	objType, err := conn.describeObjectType(name)
+	if err != nil {
+		return nil, err
+	}
+
+	if conn.objectTypes == nil {
+		conn.objectTypes = make(map[string]*objectType)
+	}
+	conn.objectTypes[name] = objType
+	conn.objectTypes[objType.name] = objType
+
+	return objType, nil
+}
+
+// describeObjectType calls OCIDescribeAny to get the attributes of the object type,
+// then OCITypeByName to get the type descriptor object
+func (conn *Conn) describeObjectType(name string) (*objectType, error) {
+	describeP, _, err := conn.ociHandleAlloc(C.OCI_HTYPE_DESCRIBE, 0)
+	if err != nil {
+		return nil, fmt.Errorf("allocate describe handle error: %v", err)
+	}
+	defer C.OCIHandleFree(*describeP, C.OCI_HTYPE_DESCRIBE)
+
+	nameP := cString(name)
+	defer C.free(unsafe.Pointer(nameP))
+
+	result := C.OCIDescribeAny(
+		conn.svc,                     // service context handle
+		conn.errHandle,               // error handle
+		unsafe.Pointer(nameP),        // the name of the object to describe
+		C.ub4(len(name)),             // the length of the name
+		C.OCI_OTYPE_NAME,             // the object is a name
+		C.OCI_DEFAULT,                // info level, unused
+		C.OCI_PTYPE_TYPE,             // the type of object to describe: a type
+		(*C.OCIDescribe)(*describeP), // describe handle
+	)
+	err = conn.getError(result)
+	if err != nil {
+		return nil, fmt.Errorf("describe object type %v error: %v", name, err)
+	}
+
+	var param *C.OCIParam
+	result = C.OCIAttrGet(
+		*describeP,             // describe handle
+		C.OCI_HTYPE_DESCRIBE,   // handle type
+		unsafe.Pointer(&param), // the parameter descriptor of the type
+		nil,                    // size not needed
+		C.OCI_ATTR_PARAM,       // attribute type
+		conn.errHandle,         // error handle
+	)
+	err = conn.getError(result)
+	if err != nil {
+		return nil, err
+	}
+
+	var typeCode C.OCITypeCode
+	_, err = conn.ociAttrGet(param, unsafe.Pointer(&typeCode), C.OCI_ATTR_TYPECODE)
+	if err != nil {
+		return nil, err
+	}
+	if typeCode != C.OCI_TYPECODE_OBJECT && typeCode != C.OCI_TYPECODE_NAMEDCOLLECTION {
+		return nil, fmt.Errorf("%v is not an object or collection type", name)
+	}
+
+	schemaName, err := conn.paramString(param, C.OCI_ATTR_SCHEMA_NAME)
+	if err != nil {
+		return nil, err
+	}
+	typeName, err := conn.paramString(param, C.OCI_ATTR_NAME)
+	if err != nil {
+		return nil, err
+	}
+
+	objType := &objectType{
+		conn:     conn,
+		name:     schemaName + "." + typeName,
+		typeCode: typeCode,
+	}
+
+	schemaNameP := cString(schemaName)
+	defer C.free(unsafe.Pointer(schemaNameP))
+	typeNameP := cString(typeName)
+	defer C.free(unsafe.Pointer(typeNameP))
+
+	result = C.OCITypeByName(
+		conn.env,               // environment handle
+		conn.errHandle,         // error handle
+		conn.svc,               // service context handle
+		schemaNameP,            // schema name
+		C.ub4(len(schemaName)), // schema name length
+		typeNameP,              // type name
+		C.ub4(len(typeName)),   // type name length
+		nil,                    // version name, unused
+		0,                      // version name length
+		C.OCI_DURATION_SESSION, // pin duration
+		C.OCI_TYPEGET_ALL,      // load the type and its attributes
+		&objType.tdo,           // the type descriptor object
+	)
+	err = conn.getError(result)
+	if err != nil {
+		return nil, fmt.Errorf("type by name %v error: %v", objType.name, err)
+	}
+
+	if typeCode == C.OCI_TYPECODE_NAMEDCOLLECTION {
+		var elementParam *C.OCIParam
+		_, err = conn.ociAttrGet(param, unsafe.Pointer(&elementParam), C.OCI_ATTR_COLLECTION_ELEMENT)
+		if err != nil {
+			return nil, err
+		}
+		objType.element = &objectAttribute{}
+		err = conn.describeDataType(elementParam, objType.element)
+		if err != nil {
+			return nil, fmt.Errorf("collection type %v element error: %v", objType.name, err)
+		}
+		return objType, nil
+	}
+
+	var attributeCount C.ub2
+	_, err = conn.ociAttrGet(param, unsafe.Pointer(&attributeCount), C.OCI_ATTR_NUM_TYPE_ATTRS)
+	if err != nil {
+		return nil, err
+	}
+	var attributeList *C.OCIParam
+	_, err = conn.ociAttrGet(param, unsafe.Pointer(&attributeList), C.OCI_ATTR_LIST_TYPE_ATTRS)
+	if err != nil {
+		return nil, err
+	}
+
+	objType.attributes = make([]objectAttribute, int(attributeCount))
+	for i := range objType.attributes {
+		// parameters of a describe handle are freed with the describe handle
+		var attributeParam *C.OCIParam
+		result = C.OCIParamGet(
+			unsafe.Pointer(attributeList), // the attribute list
+			C.OCI_DTYPE_PARAM,             // handle type
+			conn.errHandle,                // error handle
+			(*unsafe.Pointer)(unsafe.Pointer(&attributeParam)), // the parameter descriptor of the attribute
+			C.ub4(i+1), // position in the list
+		)
+		err = conn.getError(result)
+		if err != nil {
+			return nil, err
+		}
+		err = conn.describeObjectAttribute(attributeParam, &objType.attributes[i])
+		if err != nil {
+			return nil, fmt.Errorf("object type %v attribute %v error: %v", objType.name, i+1, err)
+		}
+	}
+
+	return objType, nil
+}
+
+// describeObjectAttribute sets the object attribute from the attribute parameter
+func (conn *Conn) describeObjectAttribute(param *C.OCIParam, attribute *objectAttribute) error {
+	var err error
+	attribute.name, err = conn.paramString(param, C.OCI_ATTR_NAME)
+	if err != nil {
+		return err
+	}
+	return conn.describeDataType(param, attribute)
+}
+
+// describeDataType sets the data type of the object attribute, or collection element, from the parameter
+func (conn *Conn) describeDataType(param *C.OCIParam, attribute *objectAttribute) error {
+	_, err := conn.ociAttrGet(param, unsafe.Pointer(&attribute.typeCode), C.OCI_ATTR_TYPECODE)
+	if err != nil {
+		return err
+	}
+
+	switch attribute.typeCode {
+	case C.OCI_TYPECODE_NUMBER, C.OCI_TYPECODE_DECIMAL:
+		_, err = conn.ociAttrGet(param, unsafe.Pointer(&attribute.precision), C.OCI_ATTR_PRECISION)
+		if err != nil {
+			return err
+		}
+		_, err = conn.ociAttrGet(param, unsafe.Pointer(&attribute.scale), C.OCI_ATTR_SCALE)
+		if err != nil {
+			return err
+		}
+
+	case C.OCI_TYPECODE_OBJECT, C.OCI_TYPECODE_NAMEDCOLLECTION:
+		var schemaName, typeName string
+		schemaName, err = conn.paramString(param, C.OCI_ATTR_SCHEMA_NAME)
+		if err != nil {
+			return err
+		}
+		typeName, err = conn.paramString(param, C.OCI_ATTR_TYPE_NAME)
+		if err != nil {
+			return err
+		}
+		attribute.objectType, err = conn.objectTypeByName(schemaName + "." + typeName)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// paramString returns a text attribute of a parameter descriptor
+func (conn *Conn) paramString(param *C.OCIParam, attributeType C.ub4) (string, error) {
+	var text *C.OraText
+	size, err := conn.ociAttrGet(param, unsafe.Pointer(&text), attributeType)
+	if err != nil {
+		return "", err
+	}
+	return cGoStringN(text, int(size)), nil
+}
+
+// newObjectInstance returns an object instance for a define or bind of the object type
+func newObjectInstance(objType *objectType) *objectInstance {
+	return &objectInstance{
+		objectType: objType,
+		pointers:   C.calloc(2, C.size_t(sizeOfNilPointer)),
+	}
+}
+
+// instance returns the pointer to the object instance
+func (instance *objectInstance) instance() *unsafe.Pointer {
+	return (*unsafe.Pointer)(instance.pointers)
+}
+
+// nullStruct returns the pointer to the null indicator struct of the object instance
+func (instance *objectInstance) nullStruct() *unsafe.Pointer {
+	return (*unsafe.Pointer)(unsafe.Pointer(uintptr(instance.pointers) + sizeOfNilPointer))
+}
+
+// value returns the Object or Collection of the instance, or nil if it is null
+func (instance *objectInstance) value() (interface{}, error) {
+	return instance.objectType.value(*instance.instance(), *instance.nullStruct())
+}
+
+// free frees the object instance and the pointers
+func (instance *objectInstance) free() {
+	if instance.pointers == nil {
+		return
+	}
+	if *instance.instance() != nil {
+		conn := instance.objectType.conn
+		C.OCIObjectFree(conn.env, conn.errHandle, *instance.instance(), C.OCI_OBJECTFREE_FORCE)
+	}
+	C.free(instance.pointers)
+	instance.pointers = nil
+}
+
+// value returns the Object of an object instance, or the Collection of a collection instance,
+// or nil if the instance is null
+func (objType *objectType) value(instance unsafe.Pointer, nullStruct unsafe.Pointer) (interface{}, error) {
+	if instance == nil || nullStruct == nil || *(*C.OCIInd)(nullStruct) == C.OCI_IND_NULL {
+		return nil, nil
+	}
+	if objType.typeCode == C.OCI_TYPECODE_NAMEDCOLLECTION {
+		return objType.collectionValue(instance)
+	}
+
+	object := Object{
+		TypeName:   objType.name,
+		Attributes: make(map[string]interface{}, len(objType.attributes)),
+	}
+	for i := range objType.attributes {
+		value, err := objType.getAttribute(instance, nullStruct, &objType.attributes[i])
+		if err != nil {
+			return nil, fmt.Errorf("object type %v attribute %v error: %v", objType.name, objType.attributes[i].name, err)
+		}
+		object.Attributes[objType.attributes[i].name] = value
+	}
+
+	return object, nil
+}
+
+// getAttribute calls OCIObjectGetAttr then returns the Go value of the attribute
+func (objType *objectType) getAttribute(instance unsafe.Pointer, nullStruct unsafe.Pointer, attribute *objectAttribute) (interface{}, error) {
+	conn := objType.conn
+	name := cString(attribute.name)
+	defer C.free(unsafe.Pointer(name))
+	names := []*C.oratext{(*C.oratext)(name)}
+	lengths := []C.ub4{C.ub4(len(attribute.name))}
+
+	var nullStatus C.OCIInd
+	var attributeNullStruct unsafe.Pointer
+	var attributeValue unsafe.Pointer
+	var attributeTDO *C.OCIType
+	result := C.OCIObjectGetAttr(
+		conn.env,             // environment handle
+		conn.errHandle,       // error handle
+		instance,             // the object instance
+		nullStruct,           // the null indicator struct of the instance
+		objType.tdo,          // the type descriptor object of the instance
+		&names[0],            // the attribute names
+		&lengths[0],          // the attribute name lengths
+		1,                    // number of names
+		nil,                  // array indexes, unused
+		0,                    // number of array indexes
+		&nullStatus,          // the null status of the attribute
+		&attributeNullStruct, // the null indicator struct of an object attribute
+		&attributeValue,      // pointer to the attribute value
+		&attributeTDO,        // the type descriptor object of the attribute
+	)
+	err := conn.getError(result)
+	if err != nil {
+		return nil, err
+	}
+
+	if nullStatus == C.OCI_IND_NULL {
+		return nil, nil
+	}
+
+	return conn.attributeGoValue(attribute, attributeValue, attributeNullStruct)
+}
+
+// attributeGoValue returns the Go value of a not null attribute or collection element.
+// The value is a pointer to the attribute value, and nullStruct is the null indicator struct of an object value.
+func (conn *Conn) attributeGoValue(attribute *objectAttribute, attributeValue unsafe.Pointer, nullStruct unsafe.Pointer) (interface{}, error) {
+	switch attribute.typeCode {
+
+	case C.OCI_TYPECODE_VARCHAR2, C.OCI_TYPECODE_VARCHAR, C.OCI_TYPECODE_CHAR:
+		ociString := *(**C.OCIString)(attributeValue)
+		return C.GoStringN((*C.char)(unsafe.Pointer(C.OCIStringPtr(conn.env, ociString))), C.int(C.OCIStringSize(conn.env, ociString))), nil
+
+	case C.OCI_TYPECODE_RAW:
+		ociRaw := *(**C.OCIRaw)(attributeValue)
+		return C.GoBytes(unsafe.Pointer(C.OCIRawPtr(conn.env, ociRaw)), C.int(C.OCIRawSize(conn.env, ociRaw))), nil
+
+	case C.OCI_TYPECODE_NUMBER, C.OCI_TYPECODE_DECIMAL, C.OCI_TYPECODE_INTEGER, C.OCI_TYPECODE_SMALLINT,
+		C.OCI_TYPECODE_FLOAT, C.OCI_TYPECODE_REAL, C.OCI_TYPECODE_DOUBLE, C.OCI_TYPECODE_OCTET:
+		// OCINumber is the length followed by the Oracle NUMBER format
+		number := (*[numberMaxLength]byte)(attributeValue)
+		length := int(number[0])
+		if length < 1 || length > numberMaxLength-1 {
+			return nil, fmt.Errorf("invalid number length %v", length)
+		}
+		return conn.objectNumberValue(number[1:1+length], attribute)
+
+	case C.OCI_TYPECODE_BFLOAT:
+		return float64(*(*C.float)(attributeValue)), nil
+
+	case C.OCI_TYPECODE_BDOUBLE:
+		return float64(*(*C.double)(attributeValue)), nil
+
+	case C.OCI_TYPECODE_DATE:
+		date := (*C.OCIDate)(attributeValue)
+		return time.Date(int(date.OCIDateYYYY), time.Month(date.OCIDateMM), int(date.OCIDateDD),
+			int(date.OCIDateTime.OCITimeHH), int(date.OCIDateTime.OCITimeMI), int(date.OCIDateTime.OCITimeSS), 0, conn.timeLocation), nil
+
+	case C.OCI_TYPECODE_TIMESTAMP:
+		aTime, err := conn.ociDateTimeToTime(*(**C.OCIDateTime)(attributeValue), false)
+		if err != nil {
+			return nil, err
+		}
+		return *aTime, nil
+
+	case C.OCI_TYPECODE_TIMESTAMP_TZ, C.OCI_TYPECODE_TIMESTAMP_LTZ:
+		aTime, err := conn.ociDateTimeToTime(*(**C.OCIDateTime)(attributeValue), true)
+		if err != nil {
+			return nil, err
+		}
+		return *aTime, nil
+
+	case C.OCI_TYPECODE_CLOB:
+		buffer, err := conn.ociLobRead(*(**C.OCILobLocator)(attributeValue), C.SQLCS_IMPLICIT)
+		if err != nil {
+			return nil, err
+		}
+		return string(buffer), nil
+
+	case C.OCI_TYPECODE_BLOB:
+		return conn.ociLobRead(*(**C.OCILobLocator)(attributeValue), C.SQLCS_IMPLICIT)
+
+	case C.OCI_TYPECODE_OBJECT:
+		// an embedded object is the attribute value
+		return attribute.objectType.value(attributeValue, nullStruct)
+
+	case C.OCI_TYPECODE_NAMEDCOLLECTION:
+		return attribute.objectType.collectionValue(*(*unsafe.Pointer)(attributeValue))
+
+	}
+
+	return nil, fmt.Errorf("unsupported attribute type code %v", attribute.typeCode)
+}
+
+// objectNumberValue returns the Go value of an Oracle NUMBER attribute, using the connection number type
+func (conn *Conn) objectNumberValue(buffer []byte, attribute *objectAttribute) (interface{}, error) {
+	isInteger := attribute.typeCode == C.OCI_TYPECODE_INTEGER || attribute.typeCode == C.OCI_TYPECODE_SMALLINT ||
+		(attribute.precision != 0 && attribute.scale == 0)
+
+	switch conn.numberType {
+	case numberTypeFloat:
+		number, err := decodeNumber(buffer)
+		if err != nil {
+			return nil, err
+		}
+		if isInteger {
+			integer, err := strconv.ParseInt(number, 10, 64)
+			if err == nil {
+				return integer, nil
+			}
+		}
+		return strconv.ParseFloat(number, 64)
+	case numberTypeBig:
+		if isInteger {
+			return decodeNumberAs(buffer, numberTypeBigInt)
+		}
+		return decodeNumberAs(buffer, numberTypeBigRat)
+	}
+
+	return decodeNumberAs(buffer, conn.numberType)
+}
+
+// newInstance calls OCIObjectNew to make an object instance with the attributes of object,
+// then returns the instance and its null indicator struct.
+// A nil object or nil Attributes makes a null object. The instance must be freed with OCIObjectFree.
+func (objType *objectType) newInstance(object *Object) (unsafe.Pointer, unsafe.Pointer, error) {
+	conn := objType.conn
+	instance, nullStruct, err := objType.objectNew()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if object == nil || object.Attributes == nil {
+		*(*C.OCIInd)(nullStruct) = C.OCI_IND_NULL
+		return instance, nullStruct, nil
+	}
+	*(*C.OCIInd)(nullStruct) = C.OCI_IND_NOTNULL
+
+	for name := range object.Attributes {
+		if objType.attribute(name) == nil {
+			C.OCIObjectFree(conn.env, conn.errHandle, instance, C.OCI_OBJECTFREE_FORCE)
+			return nil, nil, fmt.Errorf("object type %v has no attribute %v", objType.name, name)
+		}
+	}
+
+	for i := range objType.attributes {
+		attribute := &objType.attributes[i]
+		err = objType.setAttribute(instance, nullStruct, attribute, object.Attributes[attribute.name])
+		if err != nil {
+			C.OCIObjectFree(conn.env, conn.errHandle, instance, C.OCI_OBJECTFREE_FORCE)
+			return nil, nil, fmt.Errorf("object type %v attribute %v error: %v", objType.name, attribute.name, err)
+		}
+	}
+
+	return instance, nullStruct, nil
+}
+
+// objectNew calls OCIObjectNew to make a value instance of the object or collection type,
+// then returns the instance and its null indicator struct
+func (objType *objectType) objectNew() (unsafe.Pointer, unsafe.Pointer, error) {
+	conn := objType.conn
+	var instance unsafe.Pointer
+	result := C.OCIObjectNew(
+		conn.env,               // environment handle
+		conn.errHandle,         // error handle
+		conn.svc,               // service context handle
+		objType.typeCode,       // type code
+		objType.tdo,            // type descriptor object
+		nil,                    // table, unused for values
+		C.OCI_DURATION_SESSION, // allocation duration
+		C.TRUE,                 // allocate a value instance instead of a referenceable object
+		&instance,              // the new instance
+	)
+	err := conn.getError(result)
+	if err != nil {
+		return nil, nil, fmt.Errorf("object new %v error: %v", objType.name, err)
+	}
+
+	var nullStruct unsafe.Pointer
+	result = C.OCIObjectGetInd(conn.env, conn.errHandle, instance, &nullStruct)
+	err = conn.getError(result)
+	if err != nil {
+		C.OCIObjectFree(conn.env, conn.errHandle, instance, C.OCI_OBJECTFREE_FORCE)
+		return nil, nil, err
+	}
+
+	return instance, nullStruct, nil
+}
+
+// attribute returns the attribute with the name, or nil if there is no such attribute
+func (objType *objectType) attribute(name string) *objectAttribute {
+	for i := range objType.attributes {
+		if objType.attributes[i].name == name {
+			return &objType.attributes[i]
+		}
+	}
+	return nil
+}
+
+// setAttribute converts a Go value to the attribute type then calls OCIObjectSetAttr
+func (objType *objectType) setAttribute(instance unsafe.Pointer, nullStruct unsafe.Pointer, attribute *objectAttribute, value interface{}) error {
+	conn := objType.conn
+
+	nullStatus := C.OCIInd(C.OCI_IND_NOTNULL)
+	attributeValue, attributeNullStruct, freeValue, err := objType.attributeValue(attribute, value)
+	if err != nil {
+		return err
+	}
+	if freeValue != nil {
+		defer freeValue()
+	}
+	if attributeValue == nil {
+		nullStatus = C.OCI_IND_NULL
+	}
+
+	name := cString(attribute.name)
+	defer C.free(unsafe.Pointer(name))
+	names := []*C.oratext{(*C.oratext)(name)}
+	lengths := []C.ub4{C.ub4(len(attribute.name))}
+
+	result := C.OCIObjectSetAttr(
+		conn.env,            // environment handle
+		conn.errHandle,      // error handle
+		instance,            // the object instance
+		nullStruct,          // the null indicator struct of the instance
+		objType.tdo,         // the type descriptor object of the instance
+		&names[0],           // the attribute names
+		&lengths[0],         // the attribute name lengths
+		1,                   // number of names
+		nil,                 // array indexes, unused
+		0,                   // number of array indexes
+		nullStatus,          // the null status of the attribute
+		attributeNullStruct, // the null indicator struct of an object attribute
+		attributeValue,      // the attribute value
+	)
+	return conn.getError(result)
+}
+
+// attributeValue converts a Go value to an attribute value for OCIObjectSetAttr.
+// Returns the value, the null indicator struct for object values, and a function to free the value.
+// A nil value is returned for a null value.
+func (objType *objectType) attributeValue(attribute *objectAttribute, value interface{}) (unsafe.Pointer, unsafe.Pointer, func(), error) {
+	conn := objType.conn
+
+	if attribute.typeCode == C.OCI_TYPECODE_NAMEDCOLLECTION {
+		collection, err := collectionFromValue("", value)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		if collection == nil || collection.Elements == nil {
+			return nil, nil, nil, nil
+		}
+		instance, nullStruct, err := attribute.objectType.newCollection(collection)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		return instance, nullStruct, func() {
+			C.OCIObjectFree(conn.env, conn.errHandle, instance, C.OCI_OBJECTFREE_FORCE)
+		}, nil
+	}
+
+	if attribute.typeCode == C.OCI_TYPECODE_OBJECT {
+		var object *Object
+		switch nested := value.(type) {
+		case nil:
+			return nil, nil, nil, nil
+		case Object:
+			object = &nested
+		case *Object:
+			object = nested
+		default:
+			var err error
+			object, err = objectFromStruct("", reflect.ValueOf(value))
+			if err != nil {
+				return nil, nil, nil, err
+			}
+		}
+		if object == nil || object.Attributes == nil {
+			return nil, nil, nil, nil
+		}
+		instance, nullStruct, err := attribute.objectType.newInstance(object)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		return instance, nullStruct, func() {
+			C.OCIObjectFree(conn.env, conn.errHandle, instance, C.OCI_OBJECTFREE_FORCE)
+		}, nil
+	}
+
+	// exact numbers are not driver values
+	var number string
+	var err error
+	switch value.(type) {
+	case json.Number, *big.Int, *big.Rat:
+		number, err = numberToDecimalString(value)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		if number == "" {
+			return nil, nil, nil, nil
+		}
+		value = number
+	}
+
+	value, err = driver.DefaultParameterConverter.ConvertValue(value)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if value == nil {
+		return nil, nil, nil, nil
+	}
+
+	switch attribute.typeCode {
+
+	case C.OCI_TYPECODE_VARCHAR2, C.OCI_TYPECODE_VARCHAR, C.OCI_TYPECODE_CHAR:
+		data, ok := value.(string)
+		if !ok {
+			break
+		}
+		text := cString(data)
+		defer C.free(unsafe.Pointer(text))
+		var ociString *C.OCIString
+		result := C.OCIStringAssignText(conn.env, conn.errHandle, (*C.oratext)(text), C.ub4(len(data)), &ociString)
+		err = conn.getError(result)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		return unsafe.Pointer(ociString), nil, func() {
+			C.OCIStringResize(conn.env, conn.errHandle, 0, &ociString)
+		}, nil
+
+	case C.OCI_TYPECODE_RAW:
+		data, ok := value.([]byte)
+		if !ok {
+			break
+		}
+		var ociRaw *C.OCIRaw
+		var dataP *C.ub1
+		if len(data) > 0 {
+			dataP = (*C.ub1)(unsafe.Pointer(&data[0]))
+		}
+		result := C.OCIRawAssignBytes(conn.env, conn.errHandle, dataP, C.ub4(len(data)), &ociRaw)
+		err = conn.getError(result)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		return unsafe.Pointer(ociRaw), nil, func() {
+			C.OCIRawResize(conn.env, conn.errHandle, 0, &ociRaw)
+		}, nil
+
+	case C.OCI_TYPECODE_NUMBER, C.OCI_TYPECODE_DECIMAL, C.OCI_TYPECODE_INTEGER, C.OCI_TYPECODE_SMALLINT,
+		C.OCI_TYPECODE_FLOAT, C.OCI_TYPECODE_REAL, C.OCI_TYPECODE_DOUBLE, C.OCI_TYPECODE_OCTET:
+		switch data := value.(type) {
+		case int64:
+			number = strconv.FormatInt(data, 10)
+		case float64:
+			number = strconv.FormatFloat(data, 'g', -1, 64)
+		case bool:
+			number = "0"
+			if data {
+				number = "1"
+			}
+		case string:
+			number = data
+		default:
+			return nil, nil, nil, fmt.Errorf("can not convert %T to number", value)
+		}
+		var buffer []byte
+		buffer, err = encodeNumber(number)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		// OCINumber is the length followed by the Oracle NUMBER format
+		ociNumber := C.calloc(1, C.sizeof_OCINumber)
+		ociNumberBytes := (*[numberMaxLength]byte)(ociNumber)
+		ociNumberBytes[0] = byte(len(buffer))
+		copy(ociNumberBytes[1:], buffer)
+		return ociNumber, nil, func() { C.free(ociNumber) }, nil
+
+	case C.OCI_TYPECODE_BFLOAT, C.OCI_TYPECODE_BDOUBLE:
+		var data float64
+		switch number := value.(type) {
+		case int64:
+			data = float64(number)
+		case float64:
+			data = number
+		default:
+			return nil, nil, nil, fmt.Errorf("can not convert %T to float", value)
+		}
+		if attribute.typeCode == C.OCI_TYPECODE_BFLOAT {
+			float := (*C.float)(C.malloc(C.sizeof_float))
+			*float = C.float(data)
+			return unsafe.Pointer(float), nil, func() { C.free(unsafe.Pointer(float)) }, nil
+		}
+		double := (*C.double)(C.malloc(C.sizeof_double))
+		*double = C.double(data)
+		return unsafe.Pointer(double), nil, func() { C.free(unsafe.Pointer(double)) }, nil
+
+	case C.OCI_TYPECODE_DATE:
+		aTime, ok := value.(time.Time)
+		if !ok {
+			break
+		}
+		aTime = aTime.In(conn.timeLocation)
+		date := (*C.OCIDate)(C.calloc(1, C.sizeof_OCIDate))
+		date.OCIDateYYYY = C.sb2(aTime.Year())
+		date.OCIDateMM = C.ub1(aTime.Month())
+		date.OCIDateDD = C.ub1(aTime.Day())
+		date.OCIDateTime.OCITimeHH = C.ub1(aTime.Hour())
+		date.OCIDateTime.OCITimeMI = C.ub1(aTime.Minute())
+		date.OCIDateTime.OCITimeSS = C.ub1(aTime.Second())
+		return unsafe.Pointer(date), nil, func() { C.free(unsafe.Pointer(date)) }, nil
+
+	case C.OCI_TYPECODE_TIMESTAMP, C.OCI_TYPECODE_TIMESTAMP_TZ, C.OCI_TYPECODE_TIMESTAMP_LTZ:
+		aTime, ok := value.(time.Time)
+		if !ok {
+			break
+		}
+		var dateTimePP *unsafe.Pointer
+		dateTimePP, err = conn.timeToOCIDateTime(&aTime)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		dateTimeTZ := *dateTimePP
+		if attribute.typeCode == C.OCI_TYPECODE_TIMESTAMP_TZ {
+			return dateTimeTZ, nil, func() {
+				C.OCIDescriptorFree(dateTimeTZ, C.OCI_DTYPE_TIMESTAMP_TZ)
+			}, nil
+		}
+
+		// convert to the attribute timestamp type
+		descriptorType := C.ub4(C.OCI_DTYPE_TIMESTAMP)
+		if attribute.typeCode == C.OCI_TYPECODE_TIMESTAMP_LTZ {
+			descriptorType = C.OCI_DTYPE_TIMESTAMP_LTZ
+		}
+		var convertedPP *unsafe.Pointer
+		convertedPP, _, err = conn.ociDescriptorAlloc(descriptorType, 0)
+		if err != nil {
+			C.OCIDescriptorFree(dateTimeTZ, C.OCI_DTYPE_TIMESTAMP_TZ)
+			return nil, nil, nil, err
+		}
+		converted := *convertedPP
+		result := C.OCIDateTimeConvert(unsafe.Pointer(conn.env), conn.errHandle, (*C.OCIDateTime)(dateTimeTZ), (*C.OCIDateTime)(converted))
+		C.OCIDescriptorFree(dateTimeTZ, C.OCI_DTYPE_TIMESTAMP_TZ)
+		err = conn.getError(result)
+		if err != nil {
+			C.OCIDescriptorFree(converted, descriptorType)
+			return nil, nil, nil, err
+		}
+		return converted, nil, func() {
+			C.OCIDescriptorFree(converted, descriptorType)
+		}, nil
+
+	default:
+		return nil, nil, nil, fmt.Errorf("unsupported attribute type code %v", attribute.typeCode)
+
+	}
+
+	return nil, nil, nil, fmt.Errorf("can not convert %T to attribute type code %v", value, attribute.typeCode)
+}
+
+// makeObjectBind makes an object or collection instance bind from an *Object or *Collection.
+// If isNull is true the instance is null.
+func (stmt *Stmt) makeObjectBind(sbind *bindStruct, typeName string, value interface{}, isNull bool) error {
+	if typeName == "" {
+		return fmt.Errorf("TypeName is empty")
+	}
+	objType, err := stmt.conn.objectTypeByName(typeName)
+	if err != nil {
+		return err
+	}
+
+	sbind.dataType = C.SQLT_NTY
+	sbind.pbuf = nil
+	sbind.maxSize = 0
+	sbind.object = newObjectInstance(objType)
+	if isNull {
+		value = nil
+	}
+	*sbind.object.instance(), *sbind.object.nullStruct(), err = objType.newValue(value)
+	return err
+}
+
+// newValue makes an object instance from an *Object, or a collection instance from a *Collection,
+// then returns the instance and its null indicator. A nil value makes a null instance.
+func (objType *objectType) newValue(value interface{}) (unsafe.Pointer, unsafe.Pointer, error) {
+	if objType.typeCode == C.OCI_TYPECODE_NAMEDCOLLECTION {
+		collection, ok := value.(*Collection)
+		if !ok && value != nil {
+			return nil, nil, fmt.Errorf("%v is a collection type, can not bind %T", objType.name, value)
+		}
+		return objType.newCollection(collection)
+	}
+
+	object, ok := value.(*Object)
+	if !ok && value != nil {
+		return nil, nil, fmt.Errorf("%v is an object type, can not bind %T", objType.name, value)
+	}
+	return objType.newInstance(object)
+}
+
+// ociBindObject calls OCIBindObject with the object instance of the bind
+func (stmt *Stmt) ociBindObject(bind *bindStruct) error {
+	result := C.OCIBindObject(
+		bind.bindHandle,            // the bind handle
+		stmt.conn.errHandle,        // error handle
+		bind.object.objectType.tdo, // type descriptor object
+		bind.object.instance(),     // pointer to the object instance pointer
+		nil,                        // sizes, unused
+		bind.object.nullStruct(),   // pointer to the null indicator struct pointer
+		nil,                        // sizes, unused
+	)
+	return stmt.conn.getError(result)
+}
+
+// ociDefineObject calls OCIDefineObject with the object instance of the define.
+// OCI allocates the instance when the first row is fetched.
+func (stmt *Stmt) ociDefineObject(define *defineStruct) error {
+	result := C.OCIDefineObject(
+		define.defineHandle,          // the define handle
+		stmt.conn.errHandle,          // error handle
+		define.object.objectType.tdo, // type descriptor object
+		define.object.instance(),     // pointer to the object instance pointer
+		nil,                          // sizes, unused
+		define.object.nullStruct(),   // pointer to the null indicator struct pointer
+		nil,                          // sizes, unused
+	)
+	return stmt.conn.getError(result)
+}
@@ -35,7 +35,56 @@ import (
 //
 // prefetch_memory - the max memory for top level rows to be prefetched. Defaults to 4096. A 0 means unlimited memory.
 //
+// fetch_array_size - the number of rows fetched into the define buffers by each fetch call. Defaults to 100.
+// Queries that select a ref cursor always fetch 1 row at a time.
+//
+// number_type - the Go type NUMBER columns are returned as, can be set to: float, string, json, or big. Defaults to float.
+// float returns float64 or int64 depending on the column precision and scale, which can lose precision.
+// string returns an exact decimal string, json returns an exact json.Number,
+// and big returns *big.Int for integer columns, NUMBER(p) and NUMBER(p, 0), and *big.Rat for all other columns.
+// A *big.Rat scans into a *big.Rat variable or an interface{}, other destinations get the fraction from big.Rat String, like 1/3,
+// so use string or json to scan fractions into strings or floats, or call FloatString on the *big.Rat.
+// A NumberType query argument, like NumberTypeString, overrides number_type for the query.
+//
+// stmt_cache_size - the max number of statements in the OCI client statement cache.
+// Prepared statements are released back into the cache when closed, keyed by the statement text,
+// so preparing the same statement again does not parse it again. Defaults to 0, which disables the statement cache.
+//
+// session_pool - when true, connections get sessions from an OCI session pool instead of each connection
+// creating its own session. Closing the connection releases the session back to the pool.
+// The pool is created on first use and is shared by all connections with the same username, password, host, and pool parameters.
+// The pool is kept when no connections use it, so reconnecting does not create it again. It is destroyed after pool_idle_timeout
+// without connections, or with CloseSessionPool. Without a username, the sessions use external credentials.
+// Defaults to false. (uses strconv.ParseBool to check for true)
+//
+// pool_min - the min number of sessions in the session pool. Defaults to 0.
+//
+// pool_max - the max number of sessions in the session pool. Defaults to 10.
+//
+// pool_increment - the number of sessions opened when the session pool needs more sessions. Defaults to 1.
+//
+// pool_get_timeout - how long to wait for a session when all the sessions are busy, as a Go duration like 5s.
+// Defaults to 0, which waits until a session is available.
+//
+// pool_idle_timeout - how long a session can be idle before it is closed by the session pool, as a Go duration like 5m.
+// The timeout is in whole seconds, so it must be 0 or at least 1s. Defaults to 0, which keeps idle sessions open.
+//
+// connection_class - the Database Resident Connection Pooling (DRCP) connection class.
+// Sessions are only shared between connections with the same connection class.
+// DRCP is used when the connect string has (SERVER=POOLED) or ends with :pooled, like host/service_name:pooled
+//
+// purity - the DRCP session purity that can be set to: NEW, SELF, or DEFAULT. Defaults to DEFAULT.
+// NEW gets a session without any session state, SELF allows a session that was used before with the same connection class.
+//
 // questionph - when true, enables question mark placeholders. Defaults to false. (uses strconv.ParseBool to check for true)
+//
+// dollarph - when true, enables $1 style placeholders. Defaults to false. (uses strconv.ParseBool to check for true)
+// $N is the Nth argument, so placeholders can be out of order or repeated. A ? in the same statement is the argument of its count.
+//
+// atph - when true, enables @name style placeholders. Defaults to false. (uses strconv.ParseBool to check for true)
+// Note that an @ right after an identifier is left as is because it is a database link.
+//
+// Placeholders are only rewritten in code, not inside of string literals, q-quoted strings, quoted identifiers, or comments.
 func ParseDSN(dsnString string) (dsn *DSN, err error) {
 
 	if dsnString == "" {
@@ -51,6 +100,7 @@ func ParseDSN(dsnString string) (dsn *DSN, err error) {
 	dsn = &DSN{
 		prefetchRows:   0,
 		prefetchMemory: 4096,
+		fetchArraySize: 100,
 		operationMode:  C.OCI_DEFAULT,
 		timeLocation:   time.UTC,
 	}
@@ -91,10 +141,23 @@ func ParseDSN(dsnString string) (dsn *DSN, err error) {
 			default:
 				return nil, fmt.Errorf("Invalid isolation: %v", v[0])
 			}
-		case "questionph":
-			dsn.enableQMPlaceholders, err = strconv.ParseBool(v[0])
+		case "questionph", "dollarph", "atph":
+			var enable bool
+			enable, err = strconv.ParseBool(v[0])
 			if err != nil {
-				return nil, fmt.Errorf("Invalid questionpm: %v", v[0])
+				return nil, fmt.Errorf("Invalid %v: %v", k, v[0])
+			}
+			style := placeholderQuestion
+			switch k {
+			case "dollarph":
+				style = placeholderDollar
+			case "atph":
+				style = placeholderAt
+			}
+			if enable {
+				dsn.placeholderStyles |= style
+			} else {
+				dsn.placeholderStyles &^= style
 			}
 		case "prefetch_rows":
 			z, err := strconv.ParseUint(v[0], 10, 32)
@@ -108,6 +171,81 @@ func ParseDSN(dsnString string) (dsn *DSN, err error) {
 				return nil, fmt.Errorf("invalid prefetch_memory: %v", v[0])
 			}
 			dsn.prefetchMemory = C.ub4(z)
+		case "fetch_array_size":
+			z, err := strconv.ParseUint(v[0], 10, 32)
+			if err != nil || z < 1 {
+				return nil, fmt.Errorf("invalid fetch_array_size: %v", v[0])
+			}
+			dsn.fetchArraySize = C.ub4(z)
+		case "number_type":
+			switch v[0] {
+			case "float":
+				dsn.numberType = numberTypeFloat
+			case "string":
+				dsn.numberType = numberTypeString
+			case "json":
+				dsn.numberType = numberTypeJSON
+			case "big":
+				dsn.numberType = numberTypeBig
+			default:
+				return nil, fmt.Errorf("invalid number_type: %v", v[0])
+			}
+		case "session_pool":
+			dsn.sessionPool, err = strconv.ParseBool(v[0])
+			if err != nil {
+				return nil, fmt.Errorf("invalid session_pool: %v", v[0])
+			}
+		case "pool_min", "pool_max", "pool_increment":
+			z, err := strconv.ParseUint(v[0], 10, 32)
+			if err != nil {
+				return nil, fmt.Errorf("invalid %v: %v", k, v[0])
+			}
+			switch k {
+			case "pool_min":
+				dsn.poolMin = C.ub4(z)
+			case "pool_max":
+				dsn.poolMax = C.ub4(z)
+			default:
+				dsn.poolIncrement = C.ub4(z)
+			}
+		case "pool_get_timeout", "pool_idle_timeout":
+			duration, err := time.ParseDuration(v[0])
+			if err != nil || duration < 0 {
+				return nil, fmt.Errorf("invalid %v: %v", k, v[0])
+			}
+			if k == "pool_get_timeout" {
+				dsn.poolGetTimeout = duration
+			} else {
+				if duration > 0 && duration < time.Second {
+					// the session pool timeout is in seconds
+					return nil, fmt.Errorf("invalid %v, must be 0 or at least 1s: %v", k, v[0])
+				}
+				dsn.poolIdleTimeout = duration
+			}
+		case "stmt_cache_size":
+			z, err := strconv.ParseUint(v[0], 10, 32)
+			if err != nil {
+				return nil, fmt.Errorf("invalid stmt_cache_size: %v", v[0])
+			}
+			dsn.stmtCacheSize = C.ub4(z)
+		case "reset_package":
+			dsn.resetPackage, err = strconv.ParseBool(v[0])
+			if err != nil {
+				return nil, fmt.Errorf("invalid reset_package: %v", v[0])
+			}
+		case "connection_class":
+			dsn.connectionClass = v[0]
+		case "purity":
+			switch v[0] {
+			case "new", "NEW":
+				dsn.purity = C.OCI_ATTR_PURITY_NEW
+			case "self", "SELF":
+				dsn.purity = C.OCI_ATTR_PURITY_SELF
+			case "default", "DEFAULT":
+				dsn.purity = C.OCI_ATTR_PURITY_DEFAULT
+			default:
+				return nil, fmt.Errorf("invalid purity: %v", v[0])
+			}
 		case "as":
 			switch v[0] {
 			case "SYSDBA", "sysdba":
@@ -123,12 +261,33 @@ func ParseDSN(dsnString string) (dsn *DSN, err error) {
 		}
 	}
 
+	if dsn.sessionPool {
+		if dsn.poolMax == 0 {
+			dsn.poolMax = 10
+		}
+		if dsn.poolIncrement == 0 {
+			dsn.poolIncrement = 1
+		}
+		if dsn.poolMin > dsn.poolMax {
+			return nil, fmt.Errorf("pool_min %v is more than pool_max %v", dsn.poolMin, dsn.poolMax)
+		}
+		if dsn.operationMode != C.OCI_DEFAULT {
+			return nil, errors.New("session_pool can not be used with as")
+		}
+	}
+
 	return dsn, nil
 }
 
 // Commit transaction commit
 func (tx *Tx) Commit() error {
+	if tx.xid != nil {
+		return tx.commitXID()
+	}
+
 	tx.conn.inTransaction = false
+	tx.conn.tx = nil
+	tx.savepoints = nil
 	if rv := C.OCITransCommit(
 		tx.conn.svc,
 		tx.conn.errHandle,
@@ -141,7 +300,13 @@ func (tx *Tx) Commit() error {
 
 // Rollback transaction rollback
 func (tx *Tx) Rollback() error {
+	if tx.xid != nil {
+		return tx.rollbackXID()
+	}
+
 	tx.conn.inTransaction = false
+	tx.conn.tx = nil
+	tx.savepoints = nil
 	if rv := C.OCITransRollback(
 		tx.conn.svc,
 		tx.conn.errHandle,
@@ -168,32 +333,25 @@ func (drv *DriverStruct) Open(dsnString string) (driver.Conn, error) {
 		conn.logger = log.New(ioutil.Discard, "", 0)
 	}
 
-	// environment handle
-	var envP *C.OCIEnv
-	envPP := &envP
-	var result C.sword
-	charset := C.ub2(0)
-
-	if os.Getenv("NLS_LANG") == "" && os.Getenv("NLS_NCHAR") == "" {
-		charset = defaultCharset
+	if dsn.sessionPool {
+		err = conn.sessionPoolGet(dsn)
+		if err != nil {
+			return nil, err
+		}
+		if dsn.stmtCacheSize > 0 {
+			// the session pool statement cache is set on the pool
+			conn.stmtCacheSize = dsn.stmtCacheSize
+		}
+		conn.setDSNOptions(dsn)
+		return &conn, nil
 	}
 
-	result = C.OCIEnvNlsCreate(
-		envPP,          // pointer to a handle to the environment
-		C.OCI_THREADED, // environment mode: https://docs.oracle.com/cd/B28359_01/appdev.111/b28395/oci16rel001.htm#LNOCI87683
-		nil,            // Specifies the user-defined context for the memory callback routines.
-		nil,            // Specifies the user-defined memory allocation function. If mode is OCI_THREADED, this memory allocation routine must be thread-safe.
-		nil,            // Specifies the user-defined memory re-allocation function. If the mode is OCI_THREADED, this memory allocation routine must be thread safe.
-		nil,            // Specifies the user-defined memory free function. If mode is OCI_THREADED, this memory free routine must be thread-safe.
-		0,              // Specifies the amount of user memory to be allocated for the duration of the environment.
-		nil,            // Returns a pointer to the user memory of size xtramemsz allocated by the call for the user.
-		charset,        // The client-side character set for the current environment handle. If it is 0, the NLS_LANG setting is used.
-		charset,        // The client-side national character set for the current environment handle. If it is 0, NLS_NCHAR setting is used.
-	)
-	if result != C.OCI_SUCCESS {
-		return nil, errors.New("OCIEnvNlsCreate error")
+	// environment handle
+	var result C.sword
+	conn.env, err = ociEnvCreate()
+	if err != nil {
+		return nil, err
 	}
-	conn.env = *envPP
 
 	// defer on error handle free
 	var doneSessionBegin bool
@@ -334,6 +492,12 @@ func (drv *DriverStruct) Open(dsnString string) (driver.Conn, error) {
 			credentialType = C.OCI_CRED_RDBMS
 		}
 
+		// Database Resident Connection Pooling (DRCP) connection class and purity
+		err = conn.setSessionDRCP(unsafe.Pointer(conn.usrSession), C.OCI_HTYPE_SESSION, dsn)
+		if err != nil {
+			return nil, err
+		}
+
 		result = C.OCISessionBegin(
 			conn.svc,           // service context
 			conn.errHandle,     // error handle
@@ -355,6 +519,11 @@ func (drv *DriverStruct) Open(dsnString string) (driver.Conn, error) {
 
 	} else {
 
+		// OCILogon has no user session handle to set the DRCP attributes on
+		if len(dsn.connectionClass) > 0 || dsn.purity != C.OCI_ATTR_PURITY_DEFAULT {
+			return nil, errors.New("connection_class and purity need session_pool=true or OCISessionBegin")
+		}
+
 		var svcCtxP *C.OCISvcCtx
 		svcCtxPP := &svcCtxP
 		result = C.OCILogon(
@@ -377,13 +546,91 @@ func (drv *DriverStruct) Open(dsnString string) (driver.Conn, error) {
 
 	}
 
+	err = conn.setStmtCacheSize(dsn.stmtCacheSize)
+	if err != nil {
+		return nil, err
+	}
+
+	conn.setDSNOptions(dsn)
+
+	return &conn, nil
+}
+
+// setStmtCacheSize sets the OCI statement cache size on the service context
+func (conn *Conn) setStmtCacheSize(stmtCacheSize C.ub4) error {
+	if stmtCacheSize < 1 {
+		return nil
+	}
+	err := conn.ociAttrSet(unsafe.Pointer(conn.svc), C.OCI_HTYPE_SVCCTX, unsafe.Pointer(&stmtCacheSize), 0, C.OCI_ATTR_STMTCACHESIZE)
+	if err != nil {
+		return fmt.Errorf("statement cache size attribute set error: %v", err)
+	}
+	conn.stmtCacheSize = stmtCacheSize
+	return nil
+}
+
+// setSessionDRCP sets the Database Resident Connection Pooling (DRCP) connection class and purity
+// on a session or auth info handle
+func (conn *Conn) setSessionDRCP(handle unsafe.Pointer, handleType C.ub4, dsn *DSN) error {
+	if len(dsn.connectionClass) > 0 {
+		connectionClass := cString(dsn.connectionClass)
+		defer C.free(unsafe.Pointer(connectionClass))
+		err := conn.ociAttrSet(handle, handleType, unsafe.Pointer(connectionClass), C.ub4(len(dsn.connectionClass)), C.OCI_ATTR_CONNECTION_CLASS)
+		if err != nil {
+			return fmt.Errorf("connection class attribute set error: %v", err)
+		}
+	}
+
+	if dsn.purity != C.OCI_ATTR_PURITY_DEFAULT {
+		purity := dsn.purity
+		err := conn.ociAttrSet(handle, handleType, unsafe.Pointer(&purity), 0, C.OCI_ATTR_PURITY)
+		if err != nil {
+			return fmt.Errorf("purity attribute set error: %v", err)
+		}
+	}
+
+	return nil
+}
+
+// setDSNOptions copies the connection options from the DSN
+func (conn *Conn) setDSNOptions(dsn *DSN) {
 	conn.transactionMode = dsn.transactionMode
 	conn.prefetchRows = dsn.prefetchRows
 	conn.prefetchMemory = dsn.prefetchMemory
+	conn.fetchArraySize = dsn.fetchArraySize
+	conn.numberType = dsn.numberType
 	conn.timeLocation = dsn.timeLocation
-	conn.enableQMPlaceholders = dsn.enableQMPlaceholders
+	conn.placeholderStyles = dsn.placeholderStyles
+	conn.resetPackage = dsn.resetPackage
+}
 
-	return &conn, nil
+// ociEnvCreate calls OCIEnvNlsCreate to create a threaded environment handle with object support
+func ociEnvCreate() (*C.OCIEnv, error) {
+	var envP *C.OCIEnv
+	envPP := &envP
+	charset := C.ub2(0)
+
+	if os.Getenv("NLS_LANG") == "" && os.Getenv("NLS_NCHAR") == "" {
+		charset = defaultCharset
+	}
+
+	result := C.OCIEnvNlsCreate(
+		envPP,                       // pointer to a handle to the environment
+		C.OCI_THREADED|C.OCI_OBJECT, // environment mode: https://docs.oracle.com/cd/B28359_01/appdev.111/b28395/oci16rel001.htm#LNOCI87683
+		nil,                         // Specifies the user-defined context for the memory callback routines.
+		nil,                         // Specifies the user-defined memory allocation function. If mode is OCI_THREADED, this memory allocation routine must be thread-safe.
+		nil,                         // Specifies the user-defined memory re-allocation function. If the mode is OCI_THREADED, this memory allocation routine must be thread safe.
+		nil,                         // Specifies the user-defined memory free function. If mode is OCI_THREADED, this memory free routine must be thread-safe.
+		0,                           // Specifies the amount of user memory to be allocated for the duration of the environment.
+		nil,                         // Returns a pointer to the user memory of size xtramemsz allocated by the call for the user.
+		charset,                     // The client-side character set for the current environment handle. If it is 0, the NLS_LANG setting is used.
+		charset,                     // The client-side national character set for the current environment handle. If it is 0, NLS_NCHAR setting is used.
+	)
+	if result != C.OCI_SUCCESS {
+		return nil, errors.New("OCIEnvNlsCreate error")
+	}
+
+	return *envPP, nil
 }
 
 // GetLastInsertId returns rowid from LastInsertId
@@ -401,15 +648,6 @@ func (result *Result) RowsAffected() (int64, error) {
 	return result.rowsAffected, result.rowsAffectedErr
 }
 
-// converts "?" characters to  :1, :2, ... :n
-func placeholders(sql string) string {
-	n := 0
-	return phre.ReplaceAllStringFunc(sql, func(string) string {
-		n++
-		return ":" + strconv.Itoa(n)
-	})
-}
-
 func timezoneToLocation(hour int64, minute int64) *time.Location {
 	if minute != 0 || hour > 14 || hour < -12 {
 		// create location with FixedZone
@@ -1,2 +1,97 @@
 #include <oci.h>
 #include <stdlib.h>
+
+// OCI_ATTR_CALL_TIMEOUT is in Oracle Client 18c and later headers
+#ifndef OCI_ATTR_CALL_TIMEOUT
+#define OCI_ATTR_CALL_TIMEOUT 531
+#endif
+
+// SQLT_BOL is in Oracle Client 12c and later headers
+#ifndef SQLT_BOL
+#define SQLT_BOL 252
+#endif
+
+// The JSON type is in Oracle Client 21c and later headers.
+// Older clients never describe JSON columns, so the functions are only placeholders for the build.
+#ifndef SQLT_JSON
+#define SQLT_JSON 119
+#define OCI_DTYPE_JSON 86
+
+typedef struct OCIJson OCIJson;
+
+static inline sword OCIJsonToBinaryBuffer(OCISvcCtx *svchp, OCIJson *jsond, void *bufptr, oraub8 *buf_sz, ub4 mode, OCIError *errhp) {
+	return OCI_INVALID_HANDLE;
+}
+
+static inline sword OCIJsonBinaryBufferLoad(OCISvcCtx *svchp, OCIJson *jsond, void *bufptr, oraub8 buf_sz, ub4 mode, OCIError *errhp) {
+	return OCI_INVALID_HANDLE;
+}
+#endif
+
+// The VECTOR type is in Oracle Client 23ai and later headers.
+// Older clients never describe VECTOR columns, so the functions are only placeholders for the build.
+#ifndef SQLT_VEC
+#define SQLT_VEC 127
+#define OCI_DTYPE_VECTOR 87
+
+typedef struct OCIVector OCIVector;
+
+static inline sword OCIVectorFromArray(OCIVector *vectord, OCIError *errhp, ub1 vformat, ub4 vdim, void *vecarray, ub4 mode) {
+	return OCI_INVALID_HANDLE;
+}
+
+static inline sword OCIVectorToArray(OCIVector *vectord, OCIError *errhp, ub1 vformat, ub4 *vdim, void *vecarray, ub4 mode) {
+	return OCI_INVALID_HANDLE;
+}
+
+static inline sword OCIVectorFromSparseArray(OCIVector *vectord, OCIError *errhp, ub1 vformat, ub4 vdim, ub4 indices, void *indarr, void *vecarray, ub4 mode) {
+	return OCI_INVALID_HANDLE;
+}
+
+static inline sword OCIVectorToSparseArray(OCIVector *vectord, OCIError *errhp, ub1 vformat, ub4 *vdim, ub4 *indices, void *indarr, void *vecarray, ub4 mode) {
+	return OCI_INVALID_HANDLE;
+}
+#endif
+
+#ifndef OCI_ATTR_VECTOR_DIMENSION
+#define OCI_ATTR_VECTOR_DIMENSION 695
+#define OCI_ATTR_VECTOR_DATA_FORMAT 696
+#define OCI_ATTR_VECTOR_PROPERTY 697
+#endif
+
+#ifndef OCI_ATTR_VECTOR_PROPERTY_SPARSE
+#define OCI_ATTR_VECTOR_PROPERTY_SPARSE 0x02
+#endif
+
+#ifndef OCI_ATTR_VECTOR_FORMAT_FLOAT32
+#define OCI_ATTR_VECTOR_FORMAT_FLOAT32 2
+#define OCI_ATTR_VECTOR_FORMAT_FLOAT64 3
+#define OCI_ATTR_VECTOR_FORMAT_INT8 4
+#define OCI_ATTR_VECTOR_FORMAT_BINARY 5
+#endif
+
+// oci8ReturningBind holds the values returned into a dynamic out bind, like for a RETURNING INTO clause.
+// Each returned row has its own buffer, rows is the number of rows returned for all iterations.
+typedef struct {
+	OCIEnv   *env;
+	OCIError *errHandle;
+	ub2      dataType;
+	ub4      maxSize;
+	ub4      descriptorType;
+	ub4      rows;
+	ub4      iterStart;
+	ub4      capacity;
+	void     **buffers;
+	ub4      *lengths;
+	sb2      *indicators;
+	ub2      *rcodes;
+	sb2      nullIndicator;
+	ub4      emptyLength;
+	sb2      emptyIndicator;
+	ub2      emptyRcode;
+	sb8      emptyBuffer;
+} oci8ReturningBind;
+
+oci8ReturningBind *oci8ReturningBindNew(OCIEnv *env, OCIError *errHandle, ub2 dataType, ub4 maxSize, ub4 descriptorType);
+sword oci8ReturningBindDynamic(OCIBind *bindHandle, OCIError *errHandle, oci8ReturningBind *returningBind);
+void oci8ReturningBindFree(oci8ReturningBind *returningBind);
@@ -0,0 +1,202 @@
+package oci8
+
+import (
+	"bytes"
+	"encoding/json"
+	"math/big"
+	"reflect"
+	"testing"
+)
+
+// TestNumberEncodeDecode checks converting decimal strings to and from the Oracle NUMBER format
+func TestNumberEncodeDecode(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		number   string
+		expected []byte
+	}{
+		{"0", []byte{0x80}},
+		{"1", []byte{0xc1, 0x02}},
+		{"100", []byte{0xc2, 0x02}},
+		{"0.5", []byte{0xc0, 0x33}},
+		{"123.45", []byte{0xc2, 0x02, 0x18, 0x2e}},
+		{"-1", []byte{0x3e, 0x64, 0x66}},
+		{"-123.45", []byte{0x3d, 0x64, 0x4e, 0x38, 0x66}},
+		{"12345678901234567890123456789012345678", nil},
+		{"-12345678901234567890123456789012345678", nil},
+		{"99999999999999999999999999999999999999", nil},
+		{"1234567890123456.7891", nil},
+		{"-0.000001", nil},
+		{"0.1", nil},
+		{"-1000000000000000000000", nil},
+		{"1000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000", nil},
+		{"0.0000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000001", nil},
+		{"-1234567890123456789012345678901234567890", nil},
+	}
+
+	for _, test := range tests {
+		buffer, err := encodeNumber(test.number)
+		if err != nil {
+			t.Errorf("encodeNumber(%v) - error: %v", test.number, err)
+			continue
+		}
+		if test.expected != nil && !bytes.Equal(buffer, test.expected) {
+			t.Errorf("encodeNumber(%v) - received: %#v - expected: %#v", test.number, buffer, test.expected)
+		}
+		if len(buffer) > numberMaxLength {
+			t.Errorf("encodeNumber(%v) - length %v more than %v", test.number, len(buffer), numberMaxLength)
+		}
+
+		number, err := decodeNumber(buffer)
+		if err != nil {
+			t.Errorf("decodeNumber(%v) - error: %v", test.number, err)
+			continue
+		}
+		if number != test.number {
+			t.Errorf("decodeNumber - received: %v - expected: %v", number, test.number)
+		}
+	}
+}
+
+// TestNumberEncodeNormalize checks that encodeNumber accepts other decimal forms
+func TestNumberEncodeNormalize(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		number   string
+		expected string
+	}{
+		{"+1", "1"},
+		{"-0", "0"},
+		{"000.000", "0"},
+		{"00123.4500", "123.45"},
+		{".5", "0.5"},
+		{"5.", "5"},
+		{"1.25e3", "1250"},
+		{"1.25E-3", "0.00125"},
+		{"-12e+2", "-1200"},
+	}
+
+	for _, test := range tests {
+		buffer, err := encodeNumber(test.number)
+		if err != nil {
+			t.Errorf("encodeNumber(%v) - error: %v", test.number, err)
+			continue
+		}
+		number, err := decodeNumber(buffer)
+		if err != nil {
+			t.Errorf("decodeNumber(%v) - error: %v", test.number, err)
+			continue
+		}
+		if number != test.expected {
+			t.Errorf("number %v - received: %v - expected: %v", test.number, number, test.expected)
+		}
+	}
+
+	badNumbers := []string{"", "-", ".", "1.2.3", "1e", "1e+", "abc", "1,000", "1e99999",
+		"12345678901234567890123456789012345678901", "1e126", "1e-131"}
+	for _, number := range badNumbers {
+		_, err := encodeNumber(number)
+		if err == nil {
+			t.Errorf("encodeNumber(%q) - expected error", number)
+		}
+	}
+}
+
+// TestNumberDecodeInvalid checks decoding invalid Oracle NUMBER formats
+func TestNumberDecodeInvalid(t *testing.T) {
+	t.Parallel()
+
+	tests := [][]byte{
+		nil,
+		{0xc1},
+		{0xc1, 0x00},
+		{0xc1, 0x65},
+		{0x3e, 0x66},
+		{0x00},
+		{0xff, 0x65},
+	}
+
+	for _, test := range tests {
+		_, err := decodeNumber(test)
+		if err == nil {
+			t.Errorf("decodeNumber(%#v) - expected error", test)
+		}
+	}
+}
+
+// TestNumberTypes checks converting Go number types to and from decimal strings
+func TestNumberTypes(t *testing.T) {
+	t.Parallel()
+
+	bigInt, _ := new(big.Int).SetString("-12345678901234567890123456789012345678", 10)
+
+	tests := []struct {
+		value    interface{}
+		expected string
+		isError  bool
+	}{
+		{json.Number("123.45"), "123.45", false},
+		{bigInt, "-12345678901234567890123456789012345678", false},
+		{(*big.Int)(nil), "", false},
+		{big.NewRat(5, 4), "1.25", false},
+		{big.NewRat(-1, 80), "-0.0125", false},
+		{big.NewRat(10, 2), "5", false},
+		{(*big.Rat)(nil), "", false},
+		{big.NewRat(1, 3), "", true},
+		{"1", "", true},
+	}
+
+	for _, test := range tests {
+		actual, err := numberToDecimalString(test.value)
+		if test.isError {
+			if err == nil {
+				t.Errorf("numberToDecimalString(%v) - expected error", test.value)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("numberToDecimalString(%v) - error: %v", test.value, err)
+			continue
+		}
+		if actual != test.expected {
+			t.Errorf("numberToDecimalString(%v) - received: %v - expected: %v", test.value, actual, test.expected)
+		}
+	}
+
+	buffer, err := encodeNumber("-12345678901234567890123456789012345678")
+	if err != nil {
+		t.Fatal("encodeNumber error:", err)
+	}
+
+	decodeTests := []struct {
+		numberType numberType
+		expected   interface{}
+	}{
+		{numberTypeString, "-12345678901234567890123456789012345678"},
+		{numberTypeJSON, json.Number("-12345678901234567890123456789012345678")},
+		{numberTypeBigInt, bigInt},
+		{numberTypeBigRat, new(big.Rat).SetInt(bigInt)},
+	}
+
+	for _, test := range decodeTests {
+		actual, err := decodeNumberAs(buffer, test.numberType)
+		if err != nil {
+			t.Errorf("decodeNumberAs(%v) - error: %v", test.numberType, err)
+			continue
+		}
+		if !reflect.DeepEqual(actual, test.expected) {
+			t.Errorf("decodeNumberAs(%v) - received: %#v - expected: %#v", test.numberType, actual, test.expected)
+		}
+	}
+
+	buffer, err = encodeNumber("1.5")
+	if err != nil {
+		t.Fatal("encodeNumber error:", err)
+	}
+	_, err = decodeNumberAs(buffer, numberTypeBigInt)
+	if err == nil {
+		t.Error("decodeNumberAs big int of 1.5 - expected error")
+	}
+}
@@ -0,0 +1,196 @@
+package oci8
+
+import (
+	"database/sql"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type testObjectAddress struct {
+	Street string
+	City   sql.NullString
+}
+
+type testObjectPerson struct {
+	ID       int64 `oci8:"PERSON_ID"`
+	Name     string
+	Born     time.Time
+	Score    *float64
+	Address  testObjectAddress
+	Previous *testObjectAddress
+	Skipped  string `oci8:"-"`
+	private  string
+}
+
+// TestObjectStruct checks converting Go structs to and from objects
+func TestObjectStruct(t *testing.T) {
+	t.Parallel()
+
+	born := time.Date(2006, 1, 2, 3, 4, 5, 0, time.UTC)
+	score := 1.5
+	person := testObjectPerson{
+		ID:      1,
+		Name:    "a",
+		Born:    born,
+		Score:   &score,
+		Address: testObjectAddress{Street: "b", City: sql.NullString{String: "c", Valid: true}},
+		Skipped: "d",
+		private: "e",
+	}
+
+	object, err := (&ObjectStruct{TypeName: "PERSON", Struct: &person}).object()
+	if err != nil {
+		t.Fatal("object error:", err)
+	}
+	expected := &Object{
+		TypeName: "PERSON",
+		Attributes: map[string]interface{}{
+			"PERSON_ID": int64(1),
+			"NAME":      "a",
+			"BORN":      born,
+			"SCORE":     &score,
+			"ADDRESS": Object{Attributes: map[string]interface{}{
+				"STREET": "b",
+				"CITY":   sql.NullString{String: "c", Valid: true},
+			}},
+			"PREVIOUS": Object{},
+		},
+	}
+	if !reflect.DeepEqual(object, expected) {
+		t.Fatalf("object - received: %#v - expected: %#v", object, expected)
+	}
+
+	// as fetched
+	fetched := Object{
+		TypeName: "SCOTT.PERSON",
+		Attributes: map[string]interface{}{
+			"PERSON_ID": float64(2),
+			"NAME":      "f",
+			"BORN":      born,
+			"SCORE":     nil,
+			"ADDRESS":   nil,
+			"PREVIOUS": Object{TypeName: "SCOTT.ADDRESS", Attributes: map[string]interface{}{
+				"STREET": "g",
+				"CITY":   nil,
+			}},
+		},
+	}
+	var result testObjectPerson
+	err = (&ObjectStruct{Struct: &result}).Scan(fetched)
+	if err != nil {
+		t.Fatal("scan error:", err)
+	}
+	expectedResult := testObjectPerson{
+		ID:       2,
+		Name:     "f",
+		Born:     born,
+		Previous: &testObjectAddress{Street: "g"},
+	}
+	if !reflect.DeepEqual(result, expectedResult) {
+		t.Fatalf("scan - received: %#v - expected: %#v", result, expectedResult)
+	}
+
+	// null object
+	resultP := &result
+	err = (&ObjectStruct{Struct: &resultP}).Scan(nil)
+	if err != nil {
+		t.Fatal("scan error:", err)
+	}
+	if resultP != nil {
+		t.Fatalf("scan null - received: %#v - expected nil", resultP)
+	}
+
+	err = (&ObjectStruct{Struct: &result}).Scan(Object{Attributes: map[string]interface{}{"NAME": 1}})
+	if err == nil {
+		t.Fatal("scan number into string - expected error")
+	}
+}
+
+// TestCollectionSlice checks converting Go slices to and from collections
+func TestCollectionSlice(t *testing.T) {
+	t.Parallel()
+
+	numbers := []int64{1, 2, 3}
+	collection, err := (&CollectionSlice{TypeName: "NUMBERS", Slice: &numbers}).collection()
+	if err != nil {
+		t.Fatal("collection error:", err)
+	}
+	expected := &Collection{TypeName: "NUMBERS", Elements: []interface{}{int64(1), int64(2), int64(3)}}
+	if !reflect.DeepEqual(collection, expected) {
+		t.Fatalf("collection - received: %#v - expected: %#v", collection, expected)
+	}
+
+	// null and empty
+	var nilNumbers []int64
+	collection, err = (&CollectionSlice{TypeName: "NUMBERS", Slice: nilNumbers}).collection()
+	if err != nil {
+		t.Fatal("collection error:", err)
+	}
+	if collection.Elements != nil {
+		t.Fatalf("null collection - received: %#v - expected nil Elements", collection)
+	}
+	collection, err = (&CollectionSlice{TypeName: "NUMBERS", Slice: []int64{}}).collection()
+	if err != nil {
+		t.Fatal("collection error:", err)
+	}
+	if collection.Elements == nil || len(collection.Elements) != 0 {
+		t.Fatalf("empty collection - received: %#v - expected empty Elements", collection)
+	}
+
+	// structs are objects
+	addresses := []testObjectAddress{{Street: "a"}}
+	collection, err = (&CollectionSlice{TypeName: "ADDRESSES", Slice: addresses}).collection()
+	if err != nil {
+		t.Fatal("collection error:", err)
+	}
+	expected = &Collection{TypeName: "ADDRESSES", Elements: []interface{}{
+		Object{Attributes: map[string]interface{}{"STREET": "a", "CITY": sql.NullString{}}},
+	}}
+	if !reflect.DeepEqual(collection, expected) {
+		t.Fatalf("collection - received: %#v - expected: %#v", collection, expected)
+	}
+
+	// as fetched
+	var result []int
+	err = (&CollectionSlice{Slice: &result}).Scan(Collection{TypeName: "SCOTT.NUMBERS", Elements: []interface{}{float64(4), nil, float64(6)}})
+	if err != nil {
+		t.Fatal("scan error:", err)
+	}
+	if !reflect.DeepEqual(result, []int{4, 0, 6}) {
+		t.Fatalf("scan - received: %#v - expected: %#v", result, []int{4, 0, 6})
+	}
+
+	var nested [][]sql.NullString
+	err = (&CollectionSlice{Slice: &nested}).Scan(Collection{Elements: []interface{}{
+		Collection{Elements: []interface{}{"a", nil}},
+		nil,
+	}})
+	if err != nil {
+		t.Fatal("scan error:", err)
+	}
+	expectedNested := [][]sql.NullString{{{String: "a", Valid: true}, {}}, nil}
+	if !reflect.DeepEqual(nested, expectedNested) {
+		t.Fatalf("scan nested - received: %#v - expected: %#v", nested, expectedNested)
+	}
+
+	var people []testObjectPerson
+	err = (&CollectionSlice{Slice: &people}).Scan(Collection{Elements: []interface{}{
+		Object{Attributes: map[string]interface{}{"PERSON_ID": float64(7), "NAME": "b"}},
+	}})
+	if err != nil {
+		t.Fatal("scan error:", err)
+	}
+	if len(people) != 1 || people[0].ID != 7 || people[0].Name != "b" {
+		t.Fatalf("scan objects - received: %#v", people)
+	}
+
+	// null collection
+	err = (&CollectionSlice{Slice: &result}).Scan(nil)
+	if err != nil {
+		t.Fatal("scan error:", err)
+	}
+	if result != nil {
+		t.Fatalf("scan null - received: %#v - expected nil", result)
+	}
+}
@@ -0,0 +1,209 @@
+package oci8
+
+import (
+	"encoding/json"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestOSONEncodeDecode checks encoding Go values to OSON then decoding them
+func TestOSONEncodeDecode(t *testing.T) {
+	t.Parallel()
+
+	location := time.FixedZone("", -7*3600)
+
+	manyFields := make(map[string]interface{})
+	for i := 0; i < 300; i++ {
+		manyFields["field"+strings.Repeat("x", i%7)+string(rune('a'+i%26))+string(rune('a'+i/26))] = json.Number("1")
+	}
+
+	tests := []struct {
+		value    interface{}
+		expected interface{}
+	}{
+		{nil, nil},
+		{true, true},
+		{false, false},
+		{"", ""},
+		{"abc", "abc"},
+		{strings.Repeat("a", 300), strings.Repeat("a", 300)},
+		{strings.Repeat("b", 70000), strings.Repeat("b", 70000)},
+		{int64(-123), json.Number("-123")},
+		{uint8(200), json.Number("200")},
+		{json.Number("12345678901234567890.123456789"), json.Number("12345678901234567890.123456789")},
+		{float64(1.5), float64(1.5)},
+		{float64(-2.25), float64(-2.25)},
+		{float64(0), float64(0)},
+		{math.MaxFloat64, math.MaxFloat64},
+		{float32(-0.5), float64(-0.5)},
+		{[]byte{1, 2, 3}, []byte{1, 2, 3}},
+		{time.Date(2020, 2, 29, 23, 59, 58, 123456789, location), time.Date(2020, 2, 29, 23, 59, 58, 123456789, location)},
+		{time.Date(1999, 1, 2, 3, 4, 5, 0, time.UTC), time.Date(1999, 1, 1, 20, 4, 5, 0, location)},
+		{time.Duration(-(49*time.Hour + 2*time.Minute + 3*time.Second + 4)), time.Duration(-(49*time.Hour + 2*time.Minute + 3*time.Second + 4))},
+		{3*time.Hour + 500*time.Millisecond, 3*time.Hour + 500*time.Millisecond},
+		{map[string]interface{}{}, map[string]interface{}{}},
+		{[]interface{}{}, []interface{}{}},
+		{
+			map[string]interface{}{"a": 1, "b": []interface{}{"x", nil, true}, "c": map[string]interface{}{"a": 2.5, "d": "y"}},
+			map[string]interface{}{"a": json.Number("1"), "b": []interface{}{"x", nil, true}, "c": map[string]interface{}{"a": 2.5, "d": "y"}},
+		},
+		{[]int{1, 2, 3}, []interface{}{json.Number("1"), json.Number("2"), json.Number("3")}},
+		{map[string]string{"key": "value"}, map[string]interface{}{"key": "value"}},
+		{manyFields, manyFields},
+		{
+			json.RawMessage(`{"id": 12345678901234567890, "tags": ["a", "b"], "price": 1.10}`),
+			map[string]interface{}{"id": json.Number("12345678901234567890"), "tags": []interface{}{"a", "b"}, "price": json.Number("1.1")},
+		},
+		{
+			struct {
+				ID    int    `json:"id"`
+				Name  string `json:"name"`
+				Skip  string `json:"-"`
+				Empty string `json:",omitempty"`
+			}{ID: 1, Name: "name", Skip: "skip"},
+			map[string]interface{}{"id": json.Number("1"), "name": "name"},
+		},
+	}
+
+	for _, test := range tests {
+		image, err := encodeOSON(test.value, location)
+		if err != nil {
+			t.Errorf("encodeOSON(%T) - error: %v", test.value, err)
+			continue
+		}
+
+		value, err := decodeOSON(image, location)
+		if err != nil {
+			t.Errorf("decodeOSON(%T) - error: %v", test.value, err)
+			continue
+		}
+
+		if expected, ok := test.expected.(time.Time); ok {
+			aTime, ok := value.(time.Time)
+			if !ok || !aTime.Equal(expected) || aTime.Location() != location {
+				t.Errorf("decodeOSON - received: %v - expected: %v", value, expected)
+			}
+			continue
+		}
+		if !reflect.DeepEqual(value, test.expected) {
+			t.Errorf("decodeOSON(%T) - received: %.200v - expected: %.200v", test.value, value, test.expected)
+		}
+	}
+
+	_, err := encodeOSON(map[string]interface{}{strings.Repeat("a", 256): 1}, location)
+	if err == nil {
+		t.Error("encodeOSON long field name - expected error")
+	}
+}
+
+// TestOSONDecode checks decoding OSON images with relative offsets, inline values, and time zones
+func TestOSONDecode(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		image    []byte
+		expected interface{}
+	}{
+		{
+			// {"a": 1, "b": [true, "x"]} with 16 bit relative offsets, inline number, and inline string
+			image: []byte{0xff, 0x4a, 0x5a, 0x01, 0x21, 0x03, 0x02, 0x00, 0x04, 0x00, 0x14, 0x00, 0x00,
+				0x00, 0x01, 0x00, 0x00, 0x00, 0x02, 0x01, 0x61, 0x01, 0x62,
+				0x84, 0x02, 0x01, 0x02, 0x00, 0x08, 0x00, 0x0b, 0x21, 0xc1, 0x02,
+				0xc0, 0x02, 0x00, 0x06, 0x00, 0x07, 0x31, 0x01, 0x78},
+			expected: map[string]interface{}{"a": json.Number("1"), "b": []interface{}{true, "x"}},
+		},
+		{
+			// scalar timestamp with time zone 2024-01-02 03:04:05 UTC at +05:30
+			image: []byte{0xff, 0x4a, 0x5a, 0x01, 0x00, 0x12, 0x00, 0x0e,
+				0x7c, 120, 124, 1, 2, 4, 5, 6, 0, 0, 0, 0, 25, 90},
+			expected: time.Date(2024, 1, 2, 8, 34, 5, 0, time.FixedZone("", 19800)),
+		},
+		{
+			// scalar date 2024-01-02 03:04:05
+			image: []byte{0xff, 0x4a, 0x5a, 0x01, 0x00, 0x12, 0x00, 0x08,
+				0x3c, 120, 124, 1, 2, 4, 5, 6},
+			expected: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+		},
+		{
+			// scalar integer 301 in the node type
+			image:    []byte{0xff, 0x4a, 0x5a, 0x01, 0x00, 0x12, 0x00, 0x04, 0x43, 0xc2, 0x04, 0x02},
+			expected: json.Number("301"),
+		},
+	}
+
+	for i, test := range tests {
+		value, err := decodeOSON(test.image, time.UTC)
+		if err != nil {
+			t.Errorf("decodeOSON %v - error: %v", i, err)
+			continue
+		}
+		if expected, ok := test.expected.(time.Time); ok {
+			aTime, ok := value.(time.Time)
+			_, offset := aTime.Zone()
+			_, expectedOffset := expected.Zone()
+			if !ok || !aTime.Equal(expected) || offset != expectedOffset {
+				t.Errorf("decodeOSON %v - received: %v - expected: %v", i, value, expected)
+			}
+			continue
+		}
+		if !reflect.DeepEqual(value, test.expected) {
+			t.Errorf("decodeOSON %v - received: %v - expected: %v", i, value, test.expected)
+		}
+	}
+
+	for i, image := range [][]byte{nil, {0xff, 0x4a, 0x5b, 0x01}, tests[0].image[:len(tests[0].image)-1]} {
+		_, err := decodeOSON(image, time.UTC)
+		if err == nil {
+			t.Errorf("decodeOSON invalid %v - expected error", i)
+		}
+	}
+}
+
+// TestJSONScan checks scanning into JSON values and pointers
+func TestJSONScan(t *testing.T) {
+	t.Parallel()
+
+	src := JSON{Value: map[string]interface{}{"id": json.Number("1"), "name": "a"}}
+
+	var aJSON JSON
+	err := aJSON.Scan(src)
+	if err != nil {
+		t.Fatal("scan error:", err)
+	}
+	if !reflect.DeepEqual(aJSON, src) {
+		t.Fatalf("scan - received: %v - expected: %v", aJSON, src)
+	}
+
+	var raw json.RawMessage
+	err = (&JSON{Value: &raw}).Scan(src)
+	if err != nil {
+		t.Fatal("scan error:", err)
+	}
+	if string(raw) != `{"id":1,"name":"a"}` {
+		t.Fatalf("scan raw - received: %s", raw)
+	}
+
+	var item struct {
+		ID   int64  `json:"id"`
+		Name string `json:"name"`
+	}
+	err = (&JSON{Value: &item}).Scan(src)
+	if err != nil {
+		t.Fatal("scan error:", err)
+	}
+	if item.ID != 1 || item.Name != "a" {
+		t.Fatalf("scan struct - received: %+v", item)
+	}
+
+	aJSON = JSON{Value: "a"}
+	err = aJSON.Scan(nil)
+	if err != nil {
+		t.Fatal("scan error:", err)
+	}
+	if aJSON.Value != nil {
+		t.Fatalf("scan nil - received: %v", aJSON.Value)
+	}
+}
@@ -0,0 +1,226 @@
+package oci8
+
+import (
+	"context"
+	"database/sql"
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// TestIsArrayBind checks which values are bound as arrays
+func TestIsArrayBind(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		value    interface{}
+		expected bool
+	}{
+		{nil, false},
+		{int64(1), false},
+		{"a", false},
+		{[]byte{1, 2}, false},
+		{[]int64{1, 2}, true},
+		{[]string{"a", "b"}, true},
+		{[][]byte{{1}, {2}}, true},
+		{[]time.Time{}, true},
+		{[]sql.NullString{{String: "a", Valid: true}}, true},
+		{[]interface{}{1, nil}, true},
+	}
+
+	for _, test := range tests {
+		actual := isArrayBind(test.value)
+		if actual != test.expected {
+			t.Errorf("isArrayBind(%#v) - received: %v - expected: %v", test.value, actual, test.expected)
+		}
+	}
+}
+
+// TestArrayBindIters checks number of iterations for array binds
+func TestArrayBindIters(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		binds    []bindStruct
+		expected int
+		isError  bool
+	}{
+		{nil, 1, false},
+		{[]bindStruct{{}, {}}, 1, false},
+		{[]bindStruct{{isArray: true, arrayLength: 3}, {isArray: true, arrayLength: 3}}, 3, false},
+		{[]bindStruct{{isArray: true, arrayLength: 0}}, 0, false},
+		{[]bindStruct{{isArray: true, arrayLength: 3}, {isArray: true, arrayLength: 2}}, 0, true},
+		{[]bindStruct{{isArray: true, arrayLength: 3}, {}}, 0, true},
+	}
+
+	for i, test := range tests {
+		actual, err := arrayBindIters(test.binds)
+		if test.isError {
+			if err == nil {
+				t.Errorf("arrayBindIters test %v - expected error", i)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("arrayBindIters test %v - error: %v", i, err)
+			continue
+		}
+		if actual != test.expected {
+			t.Errorf("arrayBindIters test %v - received: %v - expected: %v", i, actual, test.expected)
+		}
+	}
+}
+
+// TestDestructiveArrayInsert checks inserting many rows with array binds
+func TestDestructiveArrayInsert(t *testing.T) {
+	if TestDisableDatabase || TestDisableDestructive {
+		t.SkipNow()
+	}
+
+	t.Parallel()
+
+	tableName := "ARRAY_INSERT_" + TestTimeString
+	err := testExec(t, "create table "+tableName+
+		" ( A INTEGER, B VARCHAR2(100), C NUMBER, D TIMESTAMP(9) WITH TIME ZONE, E RAW(100) )", nil)
+	if err != nil {
+		t.Fatal("create table error:", err)
+	}
+
+	defer testDropTable(t, tableName)
+
+	count := 1000
+	aInts := make([]int64, count)
+	bStrings := make([]sql.NullString, count)
+	cFloats := make([]float64, count)
+	dTimes := make([]time.Time, count)
+	eBytes := make([][]byte, count)
+	aTime := time.Date(2006, 1, 2, 3, 4, 5, 6, time.UTC)
+	for i := 0; i < count; i++ {
+		aInts[i] = int64(i)
+		if i%2 == 0 {
+			bStrings[i] = sql.NullString{String: "row " + strconv.Itoa(i), Valid: true}
+		}
+		cFloats[i] = float64(i) + 0.25
+		dTimes[i] = aTime.Add(time.Duration(i) * time.Second)
+		eBytes[i] = []byte{byte(i), byte(i >> 8)}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), TestContextTimeout)
+	result, err := TestDB.ExecContext(ctx, "insert into "+tableName+" ( A, B, C, D, E ) values (:1, :2, :3, :4, :5)",
+		aInts, bStrings, cFloats, dTimes, eBytes)
+	cancel()
+	if err != nil {
+		t.Fatal("exec error:", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		t.Fatal("rows affected error:", err)
+	}
+	if rowsAffected != int64(count) {
+		t.Fatalf("rows affected - received: %v - expected: %v", rowsAffected, count)
+	}
+
+	queryResults := testQueryResults{
+		query: "select count(1), count(B), sum(A), sum(C), max(D), sum(length(E)) from " + tableName,
+		queryResults: []testQueryResult{
+			{
+				results: [][]interface{}{{float64(count), float64(count / 2), float64(count * (count - 1) / 2), float64(count*(count-1)/2) + float64(count)*0.25,
+					dTimes[count-1], float64(count * 2)}},
+			},
+		},
+	}
+	testRunQueryResults(t, queryResults)
+
+	// mismatched array lengths
+	ctx, cancel = context.WithTimeout(context.Background(), TestContextTimeout)
+	_, err = TestDB.ExecContext(ctx, "insert into "+tableName+" ( A, B ) values (:1, :2)",
+		[]int64{1, 2}, []string{"a"})
+	cancel()
+	if err == nil {
+		t.Fatal("expected error for mismatched array lengths")
+	}
+}
+
+// TestDestructiveArrayInsertBatchErrors checks array insert with batch errors and row counts
+func TestDestructiveArrayInsertBatchErrors(t *testing.T) {
+	if TestDisableDatabase || TestDisableDestructive {
+		t.SkipNow()
+	}
+
+	t.Parallel()
+
+	tableName := "ARRAY_BATCH_" + TestTimeString
+	err := testExec(t, "create table "+tableName+" ( A INTEGER PRIMARY KEY, B VARCHAR2(10) )", nil)
+	if err != nil {
+		t.Fatal("create table error:", err)
+	}
+
+	defer testDropTable(t, tableName)
+
+	// rows 2 and 4 fail, duplicate key and value too large
+	aInts := []int64{1, 2, 1, 3, 4}
+	bStrings := []string{"a", "b", "c", "d", "too large value"}
+
+	var rowCounts DMLRowCounts
+	ctx, cancel := context.WithTimeout(context.Background(), TestContextTimeout)
+	_, err = TestDB.ExecContext(ctx, "insert into "+tableName+" ( A, B ) values (:1, :2)",
+		aInts, bStrings, BatchErrors, &rowCounts)
+	cancel()
+	batchError, ok := err.(*BatchError)
+	if !ok {
+		t.Fatalf("exec error - received: %T, %v - expected *BatchError", err, err)
+	}
+	if batchError.RowsAffected != 3 {
+		t.Errorf("rows affected - received: %v - expected: %v", batchError.RowsAffected, 3)
+	}
+	if len(batchError.Errors) != 2 {
+		t.Fatalf("row errors len - received: %v - expected: %v", len(batchError.Errors), 2)
+	}
+	if batchError.Errors[0].Offset != 2 || batchError.Errors[0].Code != 1 {
+		t.Errorf("row error - received: %+v - expected offset 2 and code 1", batchError.Errors[0])
+	}
+	if batchError.Errors[1].Offset != 4 || batchError.Errors[1].Code != 12899 {
+		t.Errorf("row error - received: %+v - expected offset 4 and code 12899", batchError.Errors[1])
+	}
+	expectedRowCounts := DMLRowCounts{1, 1, 0, 1, 0}
+	if !reflect.DeepEqual(rowCounts, expectedRowCounts) {
+		t.Errorf("row counts - received: %v - expected: %v", rowCounts, expectedRowCounts)
+	}
+
+	queryResults := testQueryResults{
+		query:        "select count(1) from " + tableName,
+		queryResults: []testQueryResult{{results: [][]interface{}{{float64(3)}}}},
+	}
+	testRunQueryResults(t, queryResults)
+
+	// options of an execution where the bind fails are not used by the next execution
+	ctx, cancel = context.WithTimeout(context.Background(), TestContextTimeout)
+	defer cancel()
+	conn, err := TestDB.Conn(ctx)
+	if err != nil {
+		t.Fatal("conn error:", err)
+	}
+	defer conn.Close()
+	stmt, err := conn.PrepareContext(ctx, "insert into "+tableName+" ( A, B ) values (:1, :2)")
+	if err != nil {
+		t.Fatal("prepare error:", err)
+	}
+	defer stmt.Close()
+
+	var badDest chan int
+	failedRowCounts := DMLRowCounts{-1}
+	_, err = stmt.ExecContext(ctx, []int64{5, 6}, sql.Out{Dest: &badDest}, BatchErrors, &failedRowCounts)
+	if err == nil {
+		t.Fatal("exec - expected error")
+	}
+
+	_, err = stmt.ExecContext(ctx, []int64{5, 5}, []string{"e", "f"})
+	if _, ok := err.(*BatchError); ok || err == nil {
+		t.Fatalf("exec error - received: %T, %v - expected not a *BatchError", err, err)
+	}
+	if !reflect.DeepEqual(failedRowCounts, DMLRowCounts{-1}) {
+		t.Errorf("row counts - received: %v - expected: %v", failedRowCounts, DMLRowCounts{-1})
+	}
+}
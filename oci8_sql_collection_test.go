@@ -0,0 +1,163 @@
+package oci8
+
+import (
+	"context"
+	"database/sql"
+	"reflect"
+	"testing"
+)
+
+// TestDestructiveCollection checks fetching and binding VARRAY and nested table types
+func TestDestructiveCollection(t *testing.T) {
+	if TestDisableDatabase || TestDisableDestructive {
+		t.SkipNow()
+	}
+
+	numbersType := "COLL_NUMBERS_" + TestTimeString
+	namesType := "COLL_NAMES_" + TestTimeString
+	pointType := "COLL_POINT_" + TestTimeString
+	pointsType := "COLL_POINTS_" + TestTimeString
+	tableName := "COLLECTION_" + TestTimeString
+
+	for _, create := range []struct {
+		name  string
+		query string
+	}{
+		{numbersType, "create type " + numbersType + " as table of number"},
+		{namesType, "create type " + namesType + " as varray(10) of varchar2(100)"},
+		{pointType, "create type " + pointType + " as object ( X NUMBER(10), Y NUMBER(10) )"},
+		{pointsType, "create type " + pointsType + " as table of " + pointType},
+	} {
+		err := testExec(t, create.query, nil)
+		if err != nil {
+			t.Fatal("create type error:", err)
+		}
+		typeName := create.name
+		defer func() {
+			err := testExec(t, "drop type "+typeName, nil)
+			if err != nil {
+				t.Error("drop type error:", err)
+			}
+		}()
+	}
+
+	err := testExec(t, "create table "+tableName+" ( A INTEGER, B "+namesType+", C "+pointsType+" ) "+
+		"nested table C store as "+tableName+"_C", nil)
+	if err != nil {
+		t.Fatal("create table error:", err)
+	}
+	defer testDropTable(t, tableName)
+
+	// select from table of a bound collection
+	ctx, cancel := context.WithTimeout(context.Background(), TestContextTimeout)
+	defer cancel()
+	rows, err := TestDB.QueryContext(ctx, "select column_value from table(:1) order by 1",
+		CollectionSlice{TypeName: numbersType, Slice: []int64{3, 1, 2}})
+	if err != nil {
+		t.Fatal("query error:", err)
+	}
+	var ids []int64
+	for rows.Next() {
+		var id int64
+		err = rows.Scan(&id)
+		if err != nil {
+			t.Fatal("scan error:", err)
+		}
+		ids = append(ids, id)
+	}
+	err = rows.Err()
+	if err != nil {
+		t.Fatal("rows error:", err)
+	}
+	rows.Close()
+	if !reflect.DeepEqual(ids, []int64{1, 2, 3}) {
+		t.Fatalf("table - received: %v - expected: %v", ids, []int64{1, 2, 3})
+	}
+
+	// insert
+	type point struct {
+		X int64
+		Y int64
+	}
+	ctx, cancel = context.WithTimeout(context.Background(), TestContextTimeout)
+	_, err = TestDB.ExecContext(ctx, "insert into "+tableName+" ( A, B, C ) values (1, :1, :2)",
+		Collection{TypeName: namesType, Elements: []interface{}{"a", nil, "c"}},
+		CollectionSlice{TypeName: pointsType, Slice: []point{{X: 1, Y: 2}, {X: 3, Y: 4}}})
+	cancel()
+	if err != nil {
+		t.Fatal("insert error:", err)
+	}
+
+	ctx, cancel = context.WithTimeout(context.Background(), TestContextTimeout)
+	_, err = TestDB.ExecContext(ctx, "insert into "+tableName+" ( A, B, C ) values (2, :1, :2)",
+		Collection{TypeName: namesType}, CollectionSlice{TypeName: pointsType, Slice: []point{}})
+	cancel()
+	if err != nil {
+		t.Fatal("insert error:", err)
+	}
+
+	// select as Collection
+	var schema string
+	ctx, cancel = context.WithTimeout(context.Background(), TestContextTimeout)
+	err = TestDB.QueryRowContext(ctx, "select user from dual").Scan(&schema)
+	cancel()
+	if err != nil {
+		t.Fatal("select user error:", err)
+	}
+
+	var names Collection
+	var points []point
+	ctx, cancel = context.WithTimeout(context.Background(), TestContextTimeout)
+	err = TestDB.QueryRowContext(ctx, "select B, C from "+tableName+" where A = 1").Scan(&names, &CollectionSlice{Slice: &points})
+	cancel()
+	if err != nil {
+		t.Fatal("scan error:", err)
+	}
+	expected := Collection{TypeName: schema + "." + namesType, Elements: []interface{}{"a", nil, "c"}}
+	if !reflect.DeepEqual(names, expected) {
+		t.Fatalf("varray - received: %v - expected: %v", names, expected)
+	}
+	if !reflect.DeepEqual(points, []point{{X: 1, Y: 2}, {X: 3, Y: 4}}) {
+		t.Fatalf("nested table - received: %v - expected: %v", points, []point{{X: 1, Y: 2}, {X: 3, Y: 4}})
+	}
+
+	// null and empty
+	ctx, cancel = context.WithTimeout(context.Background(), TestContextTimeout)
+	err = TestDB.QueryRowContext(ctx, "select B, C from "+tableName+" where A = 2").Scan(&names, &CollectionSlice{Slice: &points})
+	cancel()
+	if err != nil {
+		t.Fatal("scan error:", err)
+	}
+	if names.Elements != nil {
+		t.Fatalf("null varray - received: %v", names)
+	}
+	if points == nil || len(points) != 0 {
+		t.Fatalf("empty nested table - received: %#v", points)
+	}
+
+	// in out bind
+	words := []sql.NullString{{String: "x", Valid: true}, {}}
+	ctx, cancel = context.WithTimeout(context.Background(), TestContextTimeout)
+	_, err = TestDB.ExecContext(ctx, "begin :1.extend; :1(:1.count) := upper(:1(1)); end;",
+		sql.Out{Dest: &CollectionSlice{TypeName: namesType, Slice: &words}, In: true})
+	cancel()
+	if err != nil {
+		t.Fatal("in out error:", err)
+	}
+	expectedWords := []sql.NullString{{String: "x", Valid: true}, {}, {String: "X", Valid: true}}
+	if !reflect.DeepEqual(words, expectedWords) {
+		t.Fatalf("in out - received: %v - expected: %v", words, expectedWords)
+	}
+
+	// out bind
+	out := Collection{TypeName: pointsType}
+	ctx, cancel = context.WithTimeout(context.Background(), TestContextTimeout)
+	_, err = TestDB.ExecContext(ctx, "begin select C into :1 from "+tableName+" where A = 1; end;", sql.Out{Dest: &out})
+	cancel()
+	if err != nil {
+		t.Fatal("out error:", err)
+	}
+	if len(out.Elements) != 2 {
+		t.Fatalf("out - received: %v", out)
+	}
+}
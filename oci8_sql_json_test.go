@@ -0,0 +1,164 @@
+package oci8
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// TestDestructiveJSON checks fetching and binding JSON columns
+func TestDestructiveJSON(t *testing.T) {
+	if TestDisableDatabase || TestDisableDestructive {
+		t.SkipNow()
+	}
+
+	var serverVersion int64
+	ctx, cancel := context.WithTimeout(context.Background(), TestContextTimeout)
+	_, err := TestDB.ExecContext(ctx, "begin :1 := dbms_db_version.version; end;", sql.Out{Dest: &serverVersion})
+	cancel()
+	if err != nil {
+		t.Fatal("exec error:", err)
+	}
+	if clientVersionMajor < 21 || serverVersion < 21 {
+		t.Skip("JSON type needs Oracle 21 or later")
+	}
+
+	tableName := "JSON_" + TestTimeString
+	err = testExec(t, "create table "+tableName+" ( A INTEGER, B JSON )", nil)
+	if err != nil {
+		t.Fatal("create table error:", err)
+	}
+	defer testDropTable(t, tableName)
+
+	type item struct {
+		ID   int64    `json:"id"`
+		Name string   `json:"name"`
+		Tags []string `json:"tags"`
+	}
+	aTime := time.Date(2021, 2, 3, 4, 5, 6, 7000, time.UTC)
+
+	// insert
+	for _, insert := range []struct {
+		id    int64
+		value interface{}
+	}{
+		{1, map[string]interface{}{"number": json.Number("12345678901234567890.5"), "time": aTime, "array": []interface{}{"a", true, nil}}},
+		{2, JSON{Value: json.RawMessage(`{"id": 2, "name": "b", "tags": ["x", "y"]}`)}},
+		{3, item{ID: 3, Name: "c"}},
+		{4, JSON{}},
+	} {
+		ctx, cancel = context.WithTimeout(context.Background(), TestContextTimeout)
+		_, err = TestDB.ExecContext(ctx, "insert into "+tableName+" ( A, B ) values (:1, :2)", insert.id, insert.value)
+		cancel()
+		if err != nil {
+			t.Fatalf("insert %v error: %v", insert.id, err)
+		}
+	}
+
+	// select as JSON
+	var value JSON
+	ctx, cancel = context.WithTimeout(context.Background(), TestContextTimeout)
+	err = TestDB.QueryRowContext(ctx, "select B from "+tableName+" where A = 1").Scan(&value)
+	cancel()
+	if err != nil {
+		t.Fatal("scan error:", err)
+	}
+	object, ok := value.Value.(map[string]interface{})
+	if !ok {
+		t.Fatalf("select - received: %#v", value.Value)
+	}
+	if object["number"] != json.Number("12345678901234567890.5") {
+		t.Fatalf("number - received: %#v", object["number"])
+	}
+	if received, ok := object["time"].(time.Time); !ok || !received.Equal(aTime) {
+		t.Fatalf("time - received: %#v - expected: %v", object["time"], aTime)
+	}
+	if !reflect.DeepEqual(object["array"], []interface{}{"a", true, nil}) {
+		t.Fatalf("array - received: %#v", object["array"])
+	}
+
+	// select into struct and json.RawMessage
+	var received item
+	ctx, cancel = context.WithTimeout(context.Background(), TestContextTimeout)
+	err = TestDB.QueryRowContext(ctx, "select B from "+tableName+" where A = 2").Scan(&JSON{Value: &received})
+	cancel()
+	if err != nil {
+		t.Fatal("scan error:", err)
+	}
+	if !reflect.DeepEqual(received, item{ID: 2, Name: "b", Tags: []string{"x", "y"}}) {
+		t.Fatalf("struct - received: %+v", received)
+	}
+
+	var raw json.RawMessage
+	ctx, cancel = context.WithTimeout(context.Background(), TestContextTimeout)
+	err = TestDB.QueryRowContext(ctx, "select B from "+tableName+" where A = 3").Scan(&JSON{Value: &raw})
+	cancel()
+	if err != nil {
+		t.Fatal("scan error:", err)
+	}
+	if string(raw) != `{"id":3,"name":"c","tags":null}` {
+		t.Fatalf("raw - received: %s", raw)
+	}
+
+	// null
+	value = JSON{Value: "a"}
+	ctx, cancel = context.WithTimeout(context.Background(), TestContextTimeout)
+	err = TestDB.QueryRowContext(ctx, "select B from "+tableName+" where A = 4").Scan(&value)
+	cancel()
+	if err != nil {
+		t.Fatal("scan error:", err)
+	}
+	if value.Value != nil {
+		t.Fatalf("null - received: %#v", value.Value)
+	}
+
+	// out bind
+	value = JSON{}
+	ctx, cancel = context.WithTimeout(context.Background(), TestContextTimeout)
+	_, err = TestDB.ExecContext(ctx, "begin select B into :1 from "+tableName+" where A = 3; end;", sql.Out{Dest: &value})
+	cancel()
+	if err != nil {
+		t.Fatal("out error:", err)
+	}
+	if !reflect.DeepEqual(value.Value, map[string]interface{}{"id": json.Number("3"), "name": "c", "tags": nil}) {
+		t.Fatalf("out - received: %#v", value.Value)
+	}
+}
+
+// TestDestructiveJSONRawMessage checks a json.RawMessage is bound as []byte, like to BLOB and VARCHAR2 IS JSON columns
+func TestDestructiveJSONRawMessage(t *testing.T) {
+	if TestDisableDatabase || TestDisableDestructive {
+		t.SkipNow()
+	}
+
+	tableName := "JSON_RAW_" + TestTimeString
+	err := testExec(t, "create table "+tableName+" ( A INTEGER, B BLOB, C VARCHAR2(100), constraint "+tableName+"_B check (B is json), "+
+		"constraint "+tableName+"_C check (C is json) )", nil)
+	if err != nil {
+		t.Fatal("create table error:", err)
+	}
+	defer testDropTable(t, tableName)
+
+	raw := json.RawMessage(`{"id":1,"name":"a"}`)
+	ctx, cancel := context.WithTimeout(context.Background(), TestContextTimeout)
+	_, err = TestDB.ExecContext(ctx, "insert into "+tableName+" ( A, B, C ) values (1, :1, utl_raw.cast_to_varchar2(:2))", raw, raw)
+	cancel()
+	if err != nil {
+		t.Fatal("insert error:", err)
+	}
+
+	var b []byte
+	var c string
+	ctx, cancel = context.WithTimeout(context.Background(), TestContextTimeout)
+	err = TestDB.QueryRowContext(ctx, "select B, C from "+tableName+" where A = 1").Scan(&b, &c)
+	cancel()
+	if err != nil {
+		t.Fatal("scan error:", err)
+	}
+	if string(b) != string(raw) || c != string(raw) {
+		t.Fatalf("select - received: %s, %s - expected: %s", b, c, raw)
+	}
+}
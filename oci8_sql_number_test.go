@@ -3,8 +3,11 @@ package oci8
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"fmt"
 	"math"
+	"math/big"
+	"reflect"
 	"sync"
 	"testing"
 )
@@ -3873,3 +3876,138 @@ func TestDestructiveNumberColumnTypes(t *testing.T) {
 	}
 
 }
+
+// TestSelectNumberType checks selecting and binding exact numbers with number_type
+func TestSelectNumberType(t *testing.T) {
+	if TestDisableDatabase {
+		t.SkipNow()
+	}
+
+	t.Parallel()
+
+	const query = "select cast(:1 as NUMBER(38)), cast(:2 as NUMBER(20,4)), cast(:3 as NUMBER) from dual"
+	bigInt, _ := new(big.Int).SetString("-12345678901234567890123456789012345678", 10)
+	bigRat := big.NewRat(1234567890123456789, 10000)
+
+	tests := []struct {
+		numberType string
+		values     []interface{}
+		expected   []interface{}
+	}{
+		{
+			numberType: "string",
+			values:     []interface{}{bigInt, bigRat, json.Number("0.000001")},
+			expected:   []interface{}{"-12345678901234567890123456789012345678", "123456789012345.6789", "0.000001"},
+		},
+		{
+			numberType: "json",
+			values:     []interface{}{bigInt, json.Number("123456789012345.6789"), json.Number("-1e-10")},
+			expected:   []interface{}{json.Number("-12345678901234567890123456789012345678"), json.Number("123456789012345.6789"), json.Number("-0.0000000001")},
+		},
+		{
+			numberType: "big",
+			values:     []interface{}{json.Number("-12345678901234567890123456789012345678"), bigRat, (*big.Int)(nil)},
+			expected:   []interface{}{bigInt, bigRat, nil},
+		},
+	}
+
+	for _, test := range tests {
+		db := testGetDB("?number_type=" + test.numberType)
+		if db == nil {
+			t.Fatal("db is null")
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), TestContextTimeout)
+		rows, err := db.QueryContext(ctx, query, test.values...)
+		if err != nil {
+			cancel()
+			db.Close()
+			t.Fatal("query error:", err)
+		}
+
+		if !rows.Next() {
+			t.Fatal("no rows:", rows.Err())
+		}
+		results := make([]interface{}, len(test.expected))
+		dest := make([]interface{}, len(test.expected))
+		for i := range results {
+			dest[i] = &results[i]
+		}
+		err = rows.Scan(dest...)
+		if err != nil {
+			t.Fatal("scan error:", err)
+		}
+		for i := range results {
+			if !reflect.DeepEqual(results[i], test.expected[i]) {
+				t.Errorf("number type %v column %v - received: %#v - expected: %#v", test.numberType, i, results[i], test.expected[i])
+			}
+		}
+
+		err = rows.Close()
+		cancel()
+		if err != nil {
+			t.Fatal("rows close error:", err)
+		}
+
+		err = db.Close()
+		if err != nil {
+			t.Fatal("db close error:", err)
+		}
+	}
+}
+
+// TestSelectNumberTypeArgument checks the NumberType query argument overrides number_type for one query
+func TestSelectNumberTypeArgument(t *testing.T) {
+	if TestDisableDatabase {
+		t.SkipNow()
+	}
+
+	t.Parallel()
+
+	ctx, cancel := context.WithTimeout(context.Background(), TestContextTimeout)
+	defer cancel()
+	conn, err := TestDB.Conn(ctx)
+	if err != nil {
+		t.Fatal("conn error:", err)
+	}
+	defer conn.Close()
+
+	stmt, err := conn.PrepareContext(ctx, "select cast(:1 as NUMBER(20,4)) from dual")
+	if err != nil {
+		t.Fatal("prepare error:", err)
+	}
+	defer stmt.Close()
+
+	var aString string
+	err = stmt.QueryRowContext(ctx, json.Number("123456789012345.6789"), NumberTypeString).Scan(&aString)
+	if err != nil {
+		t.Fatal("scan error:", err)
+	}
+	if aString != "123456789012345.6789" {
+		t.Fatalf("string - received: %v - expected: %v", aString, "123456789012345.6789")
+	}
+
+	var bigRat *big.Rat
+	err = stmt.QueryRowContext(ctx, json.Number("1.25"), NumberTypeBig).Scan(&bigRat)
+	if err != nil {
+		t.Fatal("scan error:", err)
+	}
+	if bigRat.FloatString(2) != "1.25" {
+		t.Fatalf("big - received: %v - expected: %v", bigRat.FloatString(2), "1.25")
+	}
+
+	// the next query uses the number_type of the connection
+	var result interface{}
+	err = stmt.QueryRowContext(ctx, json.Number("1.25")).Scan(&result)
+	if err != nil {
+		t.Fatal("scan error:", err)
+	}
+	if result != float64(1.25) {
+		t.Fatalf("float - received: %#v - expected: %v", result, 1.25)
+	}
+
+	err = stmt.QueryRowContext(ctx, 1, NumberType(99)).Scan(&result)
+	if err == nil {
+		t.Fatal("unknown number type - expected error")
+	}
+}
@@ -0,0 +1,200 @@
+package oci8
+
+import (
+	"context"
+	"database/sql"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// TestDestructiveObject checks fetching and binding object types
+func TestDestructiveObject(t *testing.T) {
+	if TestDisableDatabase || TestDisableDestructive {
+		t.SkipNow()
+	}
+
+	addressType := "OBJ_ADDRESS_" + TestTimeString
+	personType := "OBJ_PERSON_" + TestTimeString
+	tableName := "OBJECT_" + TestTimeString
+
+	err := testExec(t, "create type "+addressType+" as object ( STREET VARCHAR2(100), CITY VARCHAR2(100) )", nil)
+	if err != nil {
+		t.Fatal("create type error:", err)
+	}
+	defer func() {
+		err = testExec(t, "drop type "+addressType, nil)
+		if err != nil {
+			t.Error("drop type error:", err)
+		}
+	}()
+
+	err = testExec(t, "create type "+personType+" as object ( ID NUMBER(10), NAME VARCHAR2(100), SCORE BINARY_DOUBLE, "+
+		"BORN DATE, UPDATED TIMESTAMP(9) WITH TIME ZONE, PHOTO RAW(100), ADDRESS "+addressType+" )", nil)
+	if err != nil {
+		t.Fatal("create type error:", err)
+	}
+	defer func() {
+		err = testExec(t, "drop type "+personType, nil)
+		if err != nil {
+			t.Error("drop type error:", err)
+		}
+	}()
+
+	err = testExec(t, "create table "+tableName+" ( A INTEGER, B "+personType+" )", nil)
+	if err != nil {
+		t.Fatal("create table error:", err)
+	}
+	defer testDropTable(t, tableName)
+
+	born := time.Date(2006, 1, 2, 3, 4, 5, 0, time.UTC)
+	updated := time.Date(2007, 2, 3, 4, 5, 6, 7, time.FixedZone("", 3600))
+	person := Object{
+		TypeName: personType,
+		Attributes: map[string]interface{}{
+			"ID":      int64(1),
+			"NAME":    "one",
+			"SCORE":   1.5,
+			"BORN":    born,
+			"UPDATED": updated,
+			"PHOTO":   []byte{1, 2, 3},
+			"ADDRESS": Object{Attributes: map[string]interface{}{"STREET": "street", "CITY": nil}},
+		},
+	}
+
+	// insert object, struct, and null
+	ctx, cancel := context.WithTimeout(context.Background(), TestContextTimeout)
+	_, err = TestDB.ExecContext(ctx, "insert into "+tableName+" ( A, B ) values (1, :1)", person)
+	cancel()
+	if err != nil {
+		t.Fatal("insert error:", err)
+	}
+
+	type address struct {
+		Street string
+		City   sql.NullString
+	}
+	type personStruct struct {
+		ID      int64
+		Name    string
+		Score   float64
+		Born    time.Time
+		Updated *time.Time
+		Photo   []byte
+		Address *address
+	}
+	ctx, cancel = context.WithTimeout(context.Background(), TestContextTimeout)
+	_, err = TestDB.ExecContext(ctx, "insert into "+tableName+" ( A, B ) values (2, :1)",
+		ObjectStruct{TypeName: personType, Struct: &personStruct{ID: 2, Name: "two", Born: born}})
+	cancel()
+	if err != nil {
+		t.Fatal("insert error:", err)
+	}
+
+	ctx, cancel = context.WithTimeout(context.Background(), TestContextTimeout)
+	_, err = TestDB.ExecContext(ctx, "insert into "+tableName+" ( A, B ) values (3, :1)", Object{TypeName: personType})
+	cancel()
+	if err != nil {
+		t.Fatal("insert error:", err)
+	}
+
+	// select as Object
+	var schema string
+	ctx, cancel = context.WithTimeout(context.Background(), TestContextTimeout)
+	err = TestDB.QueryRowContext(ctx, "select user from dual").Scan(&schema)
+	cancel()
+	if err != nil {
+		t.Fatal("select user error:", err)
+	}
+
+	ctx, cancel = context.WithTimeout(context.Background(), TestContextTimeout)
+	defer cancel()
+	rows, err := TestDB.QueryContext(ctx, "select B from "+tableName+" order by A")
+	if err != nil {
+		t.Fatal("query error:", err)
+	}
+	var objects []Object
+	for rows.Next() {
+		var object Object
+		err = rows.Scan(&object)
+		if err != nil {
+			t.Fatal("scan error:", err)
+		}
+		objects = append(objects, object)
+	}
+	err = rows.Err()
+	if err != nil {
+		t.Fatal("rows error:", err)
+	}
+	rows.Close()
+
+	if len(objects) != 3 {
+		t.Fatalf("objects - received: %v - expected: %v", len(objects), 3)
+	}
+	if objects[0].TypeName != schema+"."+personType {
+		t.Fatalf("type name - received: %v - expected: %v", objects[0].TypeName, schema+"."+personType)
+	}
+	updatedAttribute, _ := objects[0].Attributes["UPDATED"].(time.Time)
+	bornAttribute, _ := objects[0].Attributes["BORN"].(time.Time)
+	if !updatedAttribute.Equal(updated) || !bornAttribute.Equal(born) {
+		t.Fatalf("times - received: %v %v - expected: %v %v", bornAttribute, updatedAttribute, born, updated)
+	}
+	delete(objects[0].Attributes, "UPDATED")
+	delete(objects[0].Attributes, "BORN")
+	expected := map[string]interface{}{
+		"ID":    int64(1),
+		"NAME":  "one",
+		"SCORE": 1.5,
+		"PHOTO": []byte{1, 2, 3},
+		"ADDRESS": Object{
+			TypeName:   schema + "." + addressType,
+			Attributes: map[string]interface{}{"STREET": "street", "CITY": nil},
+		},
+	}
+	if !reflect.DeepEqual(objects[0].Attributes, expected) {
+		t.Fatalf("object - received: %v - expected: %v", objects[0].Attributes, expected)
+	}
+	if objects[1].Attributes["NAME"] != "two" || objects[1].Attributes["ADDRESS"] != nil {
+		t.Fatalf("object - received: %v", objects[1].Attributes)
+	}
+	if objects[2].Attributes != nil {
+		t.Fatalf("null object - received: %v", objects[2].Attributes)
+	}
+
+	// scan into struct
+	var aPerson *personStruct
+	ctx, cancel = context.WithTimeout(context.Background(), TestContextTimeout)
+	err = TestDB.QueryRowContext(ctx, "select B from "+tableName+" where A = 1").Scan(&ObjectStruct{Struct: &aPerson})
+	cancel()
+	if err != nil {
+		t.Fatal("scan error:", err)
+	}
+	if aPerson == nil || aPerson.Name != "one" || aPerson.Address == nil || aPerson.Address.Street != "street" ||
+		aPerson.Address.City.Valid || aPerson.Updated == nil || !aPerson.Updated.Equal(updated) {
+		t.Fatalf("scan struct - received: %#v", aPerson)
+	}
+
+	// in out bind
+	inOut := person
+	ctx, cancel = context.WithTimeout(context.Background(), TestContextTimeout)
+	_, err = TestDB.ExecContext(ctx, "begin :1.NAME := upper(:1.NAME); :1.ADDRESS := null; end;", sql.Out{Dest: &inOut, In: true})
+	cancel()
+	if err != nil {
+		t.Fatal("in out error:", err)
+	}
+	if inOut.Attributes["NAME"] != "ONE" || inOut.Attributes["ADDRESS"] != nil {
+		t.Fatalf("in out - received: %v", inOut)
+	}
+
+	// out bind
+	out := Object{TypeName: personType}
+	ctx, cancel = context.WithTimeout(context.Background(), TestContextTimeout)
+	_, err = TestDB.ExecContext(ctx, "begin select B into :1 from "+tableName+" where A = 2; end;", sql.Out{Dest: &out})
+	cancel()
+	if err != nil {
+		t.Fatal("out error:", err)
+	}
+	if out.Attributes["NAME"] != "two" {
+		t.Fatalf("out - received: %v", out)
+	}
+}
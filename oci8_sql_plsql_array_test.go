@@ -0,0 +1,155 @@
+package oci8
+
+import (
+	"context"
+	"database/sql"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// TestDestructivePLSQLArray checks binding slices as PL/SQL associative arrays
+func TestDestructivePLSQLArray(t *testing.T) {
+	if TestDisableDatabase || TestDisableDestructive {
+		t.SkipNow()
+	}
+
+	t.Parallel()
+
+	packageName := "PLSQL_ARRAY_" + TestTimeString
+	err := testExec(t, `create or replace package `+packageName+` as
+  type string_array is table of varchar2(100) index by pls_integer;
+  type number_array is table of number index by pls_integer;
+  type date_array is table of timestamp with time zone index by pls_integer;
+  procedure join_strings(p_strings in string_array, p_result out varchar2);
+  procedure make_numbers(p_count in integer, p_numbers out number_array);
+  procedure upper_strings(p_strings in out string_array);
+  procedure add_days(p_dates in out date_array);
+end;`, nil)
+	if err != nil {
+		t.Fatal("create package error:", err)
+	}
+	defer func() {
+		err = testExec(t, "drop package "+packageName, nil)
+		if err != nil {
+			t.Error("drop package error:", err)
+		}
+	}()
+
+	err = testExec(t, `create or replace package body `+packageName+` as
+  procedure join_strings(p_strings in string_array, p_result out varchar2) is
+  begin
+    for i in 1 .. p_strings.count loop
+      p_result := p_result || nvl(p_strings(i), '-') || ',';
+    end loop;
+  end;
+  procedure make_numbers(p_count in integer, p_numbers out number_array) is
+  begin
+    for i in 1 .. p_count loop
+      if mod(i, 2) = 0 then
+        p_numbers(i) := null;
+      else
+        p_numbers(i) := i * 1.5;
+      end if;
+    end loop;
+  end;
+  procedure upper_strings(p_strings in out string_array) is
+  begin
+    for i in 1 .. p_strings.count loop
+      p_strings(i) := upper(p_strings(i));
+    end loop;
+    p_strings(p_strings.count + 1) := 'END';
+  end;
+  procedure add_days(p_dates in out date_array) is
+  begin
+    for i in 1 .. p_dates.count loop
+      p_dates(i) := p_dates(i) + i;
+    end loop;
+  end;
+end;`, nil)
+	if err != nil {
+		t.Fatal("create package body error:", err)
+	}
+
+	// in
+	var result string
+	ctx, cancel := context.WithTimeout(context.Background(), TestContextTimeout)
+	_, err = TestDB.ExecContext(ctx, "begin "+packageName+".join_strings(:1, :2); end;",
+		[]sql.NullString{{String: "a", Valid: true}, {}, {String: "c", Valid: true}}, sql.Out{Dest: &result}, PLSQLArrays)
+	cancel()
+	if err != nil {
+		t.Fatal("join strings error:", err)
+	}
+	if result != "a,-,c," {
+		t.Fatalf("join strings - received: %v - expected: %v", result, "a,-,c,")
+	}
+
+	// empty in
+	result = ""
+	ctx, cancel = context.WithTimeout(context.Background(), TestContextTimeout)
+	_, err = TestDB.ExecContext(ctx, "begin "+packageName+".join_strings(:1, :2); end;",
+		[]string{}, sql.Out{Dest: &result}, PLSQLArrays)
+	cancel()
+	if err != nil {
+		t.Fatal("join strings error:", err)
+	}
+	if result != "" {
+		t.Fatalf("join strings - received: %v - expected empty", result)
+	}
+
+	// out
+	numbers := make([]sql.NullFloat64, 0, 10)
+	ctx, cancel = context.WithTimeout(context.Background(), TestContextTimeout)
+	_, err = TestDB.ExecContext(ctx, "begin "+packageName+".make_numbers(:1, :2); end;",
+		3, sql.Out{Dest: &numbers}, PLSQLArrays)
+	cancel()
+	if err != nil {
+		t.Fatal("make numbers error:", err)
+	}
+	expectedNumbers := []sql.NullFloat64{{Float64: 1.5, Valid: true}, {}, {Float64: 4.5, Valid: true}}
+	if !reflect.DeepEqual(numbers, expectedNumbers) {
+		t.Fatalf("make numbers - received: %v - expected: %v", numbers, expectedNumbers)
+	}
+	if cap(numbers) != 10 {
+		t.Fatalf("make numbers capacity - received: %v - expected: %v", cap(numbers), 10)
+	}
+
+	// out with more elements than capacity
+	numbers = make([]sql.NullFloat64, 0, 2)
+	ctx, cancel = context.WithTimeout(context.Background(), TestContextTimeout)
+	_, err = TestDB.ExecContext(ctx, "begin "+packageName+".make_numbers(:1, :2); end;",
+		3, sql.Out{Dest: &numbers}, PLSQLArrays)
+	cancel()
+	if err == nil {
+		t.Fatal("make numbers - expected error")
+	}
+
+	// in out
+	words := make([]string, 2, 5)
+	words[0] = "abc"
+	words[1] = "def"
+	ctx, cancel = context.WithTimeout(context.Background(), TestContextTimeout)
+	_, err = TestDB.ExecContext(ctx, "begin "+packageName+".upper_strings(:1); end;",
+		sql.Out{Dest: &words, In: true}, PLSQLArrays)
+	cancel()
+	if err != nil {
+		t.Fatal("upper strings error:", err)
+	}
+	if !reflect.DeepEqual(words, []string{"ABC", "DEF", "END"}) {
+		t.Fatalf("upper strings - received: %v - expected: %v", words, []string{"ABC", "DEF", "END"})
+	}
+
+	// in out time
+	aTime := time.Date(2006, 1, 2, 3, 4, 5, 0, time.UTC)
+	times := []time.Time{aTime, aTime}
+	ctx, cancel = context.WithTimeout(context.Background(), TestContextTimeout)
+	_, err = TestDB.ExecContext(ctx, "begin "+packageName+".add_days(:1); end;",
+		sql.Out{Dest: &times, In: true}, PLSQLArrays)
+	cancel()
+	if err != nil {
+		t.Fatal("add days error:", err)
+	}
+	if len(times) != 2 || !times[0].Equal(aTime.AddDate(0, 0, 1)) || !times[1].Equal(aTime.AddDate(0, 0, 2)) {
+		t.Fatalf("add days - received: %v", times)
+	}
+}
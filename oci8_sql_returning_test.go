@@ -0,0 +1,138 @@
+package oci8
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// TestIsReturningDest checks which out bind destinations are returning binds
+func TestIsReturningDest(t *testing.T) {
+	t.Parallel()
+
+	var aString string
+	var aBytes []byte
+	var nilSlice *[]int64
+
+	tests := []struct {
+		dest     interface{}
+		expected bool
+	}{
+		{nil, false},
+		{&aString, false},
+		{&aBytes, false},
+		{[]int64{}, false},
+		{nilSlice, false},
+		{&[]int64{}, true},
+		{&[]string{}, true},
+		{&[][]byte{}, true},
+		{&[]time.Time{}, true},
+		{&[]sql.NullString{}, true},
+	}
+
+	for _, test := range tests {
+		actual := isReturningDest(test.dest)
+		if actual != test.expected {
+			t.Errorf("isReturningDest(%#v) - received: %v - expected: %v", test.dest, actual, test.expected)
+		}
+	}
+}
+
+// TestDestructiveReturning checks RETURNING INTO slices for single row and multi-row DML
+func TestDestructiveReturning(t *testing.T) {
+	if TestDisableDatabase || TestDisableDestructive {
+		t.SkipNow()
+	}
+
+	t.Parallel()
+
+	tableName := "RETURNING_" + TestTimeString
+	err := testExec(t, "create table "+tableName+
+		" ( A INTEGER, B VARCHAR2(100), C BINARY_DOUBLE, D TIMESTAMP(9) WITH TIME ZONE, E RAW(100), F NUMBER(38) )", nil)
+	if err != nil {
+		t.Fatal("create table error:", err)
+	}
+
+	defer testDropTable(t, tableName)
+
+	aTime := time.Date(2006, 1, 2, 3, 4, 5, 6, time.UTC)
+
+	// single row insert
+	var ids []int64
+	ctx, cancel := context.WithTimeout(context.Background(), TestContextTimeout)
+	_, err = TestDB.ExecContext(ctx, "insert into "+tableName+" ( A, B, C, D, E, F ) values (:1, :2, :3, :4, :5, :6) returning A into :7",
+		1, "one", 1.5, aTime, []byte{1}, json.Number("12345678901234567890123456789012345678"), sql.Out{Dest: &ids})
+	cancel()
+	if err != nil {
+		t.Fatal("insert error:", err)
+	}
+	if !reflect.DeepEqual(ids, []int64{1}) {
+		t.Fatalf("ids - received: %v - expected: %v", ids, []int64{1})
+	}
+
+	// array insert
+	ctx, cancel = context.WithTimeout(context.Background(), TestContextTimeout)
+	_, err = TestDB.ExecContext(ctx, "insert into "+tableName+" ( A, B, C, D, E, F ) values (:1, :2, :3, :4, :5, :6) returning A into :7",
+		[]int64{2, 3}, []sql.NullString{{String: "two", Valid: true}, {}}, []float64{2.5, 3.5}, []time.Time{aTime, aTime},
+		[][]byte{{2}, {3}}, []int64{2, 3}, sql.Out{Dest: &ids})
+	cancel()
+	if err != nil {
+		t.Fatal("array insert error:", err)
+	}
+	if !reflect.DeepEqual(ids, []int64{2, 3}) {
+		t.Fatalf("ids - received: %v - expected: %v", ids, []int64{2, 3})
+	}
+
+	// multi-row update
+	var aInts []int
+	var bStrings []sql.NullString
+	var cFloats []float64
+	var dTimes []time.Time
+	var eBytes [][]byte
+	var fNumbers []json.Number
+	ctx, cancel = context.WithTimeout(context.Background(), TestContextTimeout)
+	result, err := TestDB.ExecContext(ctx, "update "+tableName+" set A = A * 10 returning A, B, C, D, E, F into :1, :2, :3, :4, :5, :6",
+		sql.Out{Dest: &aInts}, sql.Out{Dest: &bStrings}, sql.Out{Dest: &cFloats}, sql.Out{Dest: &dTimes},
+		sql.Out{Dest: &eBytes}, sql.Out{Dest: &fNumbers})
+	cancel()
+	if err != nil {
+		t.Fatal("update error:", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		t.Fatal("rows affected error:", err)
+	}
+	if rowsAffected != 3 || len(aInts) != 3 || len(bStrings) != 3 || len(cFloats) != 3 || len(dTimes) != 3 || len(eBytes) != 3 || len(fNumbers) != 3 {
+		t.Fatalf("rows - received: %v %v %v %v %v %v %v - expected: 3", rowsAffected, aInts, bStrings, cFloats, dTimes, eBytes, fNumbers)
+	}
+	for i := 0; i < 3; i++ {
+		switch aInts[i] {
+		case 10:
+			if bStrings[i].String != "one" || cFloats[i] != 1.5 || !reflect.DeepEqual(eBytes[i], []byte{1}) ||
+				fNumbers[i] != "12345678901234567890123456789012345678" {
+				t.Errorf("row %v - received: %v %v %v %v", i, bStrings[i], cFloats[i], eBytes[i], fNumbers[i])
+			}
+		case 30:
+			if bStrings[i].Valid {
+				t.Errorf("row %v - received: %v - expected null", i, bStrings[i])
+			}
+		}
+		if !dTimes[i].Equal(aTime) {
+			t.Errorf("row %v - received: %v - expected: %v", i, dTimes[i], aTime)
+		}
+	}
+
+	// no rows
+	ctx, cancel = context.WithTimeout(context.Background(), TestContextTimeout)
+	_, err = TestDB.ExecContext(ctx, "delete from "+tableName+" where A < 0 returning A into :1", sql.Out{Dest: &aInts})
+	cancel()
+	if err != nil {
+		t.Fatal("delete error:", err)
+	}
+	if len(aInts) != 0 {
+		t.Fatalf("delete - received: %v - expected: none", aInts)
+	}
+}
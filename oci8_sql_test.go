@@ -3,7 +3,10 @@ package oci8
 import (
 	"context"
 	"database/sql"
+	"database/sql/driver"
+	"errors"
 	"fmt"
+	"io"
 	"log"
 	"os"
 	"reflect"
@@ -18,16 +21,7 @@ import (
 func testGetDB(params string) *sql.DB {
 	Driver.Logger = log.New(os.Stderr, "oci8 ", log.Ldate|log.Ltime|log.LUTC|log.Lshortfile)
 
-	var openString string
-	// [username/[password]@]host[:port][/service_name][?param1=value1&...&paramN=valueN]
-	if len(TestUsername) > 0 {
-		if len(TestPassword) > 0 {
-			openString = TestUsername + "/" + TestPassword + "@"
-		} else {
-			openString = TestUsername + "@"
-		}
-	}
-	openString += TestHostValid + params
+	openString := testGetDSN(params)
 
 	db, err := sql.Open("oci8", openString)
 	if err != nil {
@@ -51,6 +45,20 @@ func testGetDB(params string) *sql.DB {
 	return db
 }
 
+// testGetDSN returns the DSN string for the test database with params
+func testGetDSN(params string) string {
+	var openString string
+	// [username/[password]@]host[:port][/service_name][?param1=value1&...&paramN=valueN]
+	if len(TestUsername) > 0 {
+		if len(TestPassword) > 0 {
+			openString = TestUsername + "/" + TestPassword + "@"
+		} else {
+			openString = TestUsername + "@"
+		}
+	}
+	return openString + TestHostValid + params
+}
+
 func testDropTable(t *testing.T, tableName string) {
 	err := testExec(t, "drop table "+tableName, nil)
 	if err != nil {
@@ -464,10 +472,15 @@ end;
 		t.Fatal("prepare error:", err)
 	}
 
+	// the deadline is an OCI call timeout, ORA-03156, with Oracle Client 18c and later, otherwise a break, ORA-01013
+	expected := "ORA-01013"
+	if clientVersionMajor >= 18 {
+		expected = "ORA-03156"
+	}
+
 	ctx, cancel = context.WithTimeout(context.Background(), 200*time.Millisecond)
 	_, err = stmt.ExecContext(ctx)
 	cancel()
-	expected := "ORA-01013"
 	if err == nil || len(err.Error()) < len(expected) || err.Error()[:len(expected)] != expected {
 		t.Fatalf("stmt exec - expected: %v - received: %v", expected, err)
 	}
@@ -1113,6 +1126,125 @@ end;`
 	}
 }
 
+// TestNativeBoolean tests PL/SQL BOOLEAN binds and Oracle 23 BOOLEAN columns
+func TestNativeBoolean(t *testing.T) {
+	if TestDisableDatabase {
+		t.SkipNow()
+	}
+
+	t.Parallel()
+
+	var serverVersion int64
+	ctx, cancel := context.WithTimeout(context.Background(), TestContextTimeout)
+	_, err := TestDB.ExecContext(ctx, "begin :1 := dbms_db_version.version; end;", sql.Out{Dest: &serverVersion})
+	cancel()
+	if err != nil {
+		t.Fatal("exec error:", err)
+	}
+	if clientVersionMajor < 12 || serverVersion < 12 {
+		t.Skip("native boolean needs Oracle 12.1 or later")
+	}
+
+	query := `
+declare
+	function NOT_BOOL(p_bool1 BOOLEAN) return BOOLEAN as
+	begin
+		return not p_bool1;
+	end NOT_BOOL;
+begin
+	:bool2 := NOT_BOOL(:bool1);
+end;`
+
+	var bool2 Boolean
+	ctx, cancel = context.WithTimeout(context.Background(), TestContextTimeout)
+	_, err = TestDB.ExecContext(ctx, query, sql.Named("bool1", Boolean{Bool: true, Valid: true}), sql.Named("bool2", sql.Out{Dest: &bool2}))
+	cancel()
+	if err != nil {
+		t.Fatal("exec error:", err)
+	}
+	if !bool2.Valid || bool2.Bool {
+		t.Fatalf("bool2 - received: %v - expected: %v", bool2, Boolean{Bool: false, Valid: true})
+	}
+
+	inOutBool := Boolean{Bool: false, Valid: true}
+	ctx, cancel = context.WithTimeout(context.Background(), TestContextTimeout)
+	_, err = TestDB.ExecContext(ctx, "begin :bool1 := not :bool1; end;", sql.Named("bool1", sql.Out{Dest: &inOutBool, In: true}))
+	cancel()
+	if err != nil {
+		t.Fatal("exec error:", err)
+	}
+	if !inOutBool.Valid || !inOutBool.Bool {
+		t.Fatalf("inOutBool - received: %v - expected: %v", inOutBool, Boolean{Bool: true, Valid: true})
+	}
+
+	ctx, cancel = context.WithTimeout(context.Background(), TestContextTimeout)
+	_, err = TestDB.ExecContext(ctx, "declare b boolean; begin :bool1 := b; end;", sql.Named("bool1", sql.Out{Dest: &inOutBool}))
+	cancel()
+	if err != nil {
+		t.Fatal("exec error:", err)
+	}
+	if inOutBool.Valid {
+		t.Fatal("inOutBool is Valid")
+	}
+
+	// a bool is still bound as a 0/1 number
+	query = `
+declare
+	function NOT_NUMBER(p_bool1 NUMERIC) return NUMERIC as
+	begin
+		return 1 - p_bool1;
+	end NOT_NUMBER;
+begin
+	:bool2 := NOT_NUMBER(:bool1);
+end;`
+
+	var number2 int64
+	ctx, cancel = context.WithTimeout(context.Background(), TestContextTimeout)
+	_, err = TestDB.ExecContext(ctx, query, sql.Named("bool1", true), sql.Named("bool2", sql.Out{Dest: &number2}))
+	cancel()
+	if err != nil {
+		t.Fatal("exec error:", err)
+	}
+	if number2 != 0 {
+		t.Fatalf("number2 - received: %v - expected: %v", number2, 0)
+	}
+
+	// a *bool out is a 0/1 number too
+	bool3 := true
+	ctx, cancel = context.WithTimeout(context.Background(), TestContextTimeout)
+	_, err = TestDB.ExecContext(ctx, query, sql.Named("bool1", true), sql.Named("bool2", sql.Out{Dest: &bool3}))
+	cancel()
+	if err != nil {
+		t.Fatal("exec error:", err)
+	}
+	if bool3 {
+		t.Fatal("bool3 is true")
+	}
+
+	if clientVersionMajor < 23 || serverVersion < 23 {
+		return
+	}
+
+	queryResults := testQueryResults{
+		query: "select :1, true, false, cast(null as boolean) from dual",
+		queryResults: []testQueryResult{
+			{
+				args:    []interface{}{Boolean{Bool: true, Valid: true}},
+				results: [][]interface{}{{true, true, false, nil}},
+			},
+			{
+				args:    []interface{}{Boolean{Bool: false, Valid: true}},
+				results: [][]interface{}{{false, true, false, nil}},
+			},
+			{
+				args:    []interface{}{Boolean{}},
+				results: [][]interface{}{{nil, true, false, nil}},
+			},
+		},
+	}
+	testRunQueryResults(t, queryResults)
+}
+
 // TestQuestionMark tests question mark placeholder
 func TestQuestionMark(t *testing.T) {
 	if TestDisableDatabase {
@@ -1181,6 +1313,1019 @@ func TestQuestionMark(t *testing.T) {
 	}
 }
 
+// TestDollarAndAtPlaceholders tests $1 and @name placeholders
+func TestDollarAndAtPlaceholders(t *testing.T) {
+	if TestDisableDatabase {
+		t.SkipNow()
+	}
+
+	t.Parallel()
+
+	db := testGetDB("?dollarph=true&atph=true")
+	if db == nil {
+		t.Fatal("db is null")
+	}
+
+	defer func() {
+		err := db.Close()
+		if err != nil {
+			t.Fatal("db close error:", err)
+		}
+	}()
+
+	var aString string
+	var aInt int64
+	var bInt int64
+	ctx, cancel := context.WithTimeout(context.Background(), TestContextTimeout)
+	err := db.QueryRowContext(ctx, "select $1 || '?$1@a' || q'[?]' /* $1 */, $2 from dual -- $3", "a", 2).Scan(&aString, &aInt)
+	cancel()
+	if err != nil {
+		t.Fatal("query row error:", err)
+	}
+	if aString != "a?$1@a?" || aInt != 2 {
+		t.Fatalf("dollar placeholders - received: %v, %v - expected: %v, %v", aString, aInt, "a?$1@a?", 2)
+	}
+
+	// out of order and repeated
+	var bString string
+	ctx, cancel = context.WithTimeout(context.Background(), TestContextTimeout)
+	err = db.QueryRowContext(ctx, "select $2 || $1 || $2, $1 from dual", "a", "b").Scan(&aString, &bString)
+	cancel()
+	if err != nil {
+		t.Fatal("query row error:", err)
+	}
+	if aString != "bab" || bString != "a" {
+		t.Fatalf("dollar placeholders - received: %v, %v - expected: %v, %v", aString, bString, "bab", "a")
+	}
+
+	// out of order in a PL/SQL block
+	ctx, cancel = context.WithTimeout(context.Background(), TestContextTimeout)
+	_, err = db.ExecContext(ctx, "begin $2 := $1 + $1; end;", 4, sql.Out{Dest: &aInt})
+	cancel()
+	if err != nil {
+		t.Fatal("exec error:", err)
+	}
+	if aInt != 8 {
+		t.Fatalf("dollar placeholders - received: %v - expected: %v", aInt, 8)
+	}
+
+	ctx, cancel = context.WithTimeout(context.Background(), TestContextTimeout)
+	err = db.QueryRowContext(ctx, "select @aName + 1 from dual", sql.Named("aName", 2)).Scan(&bInt)
+	cancel()
+	if err != nil {
+		t.Fatal("query row error:", err)
+	}
+	if bInt != 3 {
+		t.Fatalf("at placeholders - received: %v - expected: %v", bInt, 3)
+	}
+}
+
+// TestOraError tests OraError code, offset, and classification
+func TestOraError(t *testing.T) {
+	if TestDisableDatabase {
+		t.SkipNow()
+	}
+
+	t.Parallel()
+
+	ctx, cancel := context.WithTimeout(context.Background(), TestContextTimeout)
+	_, err := TestDB.ExecContext(ctx, "select 1 from dual where bad_column = 1")
+	cancel()
+	oraError, ok := err.(*OraError)
+	if !ok {
+		t.Fatalf("exec error - received: %T, %v - expected *OraError", err, err)
+	}
+	if oraError.Code != 904 {
+		t.Errorf("code - received: %v - expected: %v", oraError.Code, 904)
+	}
+	if oraError.Offset != 25 {
+		t.Errorf("offset - received: %v - expected: %v", oraError.Offset, 25)
+	}
+	if len(oraError.Records) < 1 || oraError.Records[0].Code != 904 {
+		t.Errorf("records - received: %v", oraError.Records)
+	}
+
+	ctx, cancel = context.WithTimeout(context.Background(), TestContextTimeout)
+	_, err = TestDB.ExecContext(ctx, "begin raise dup_val_on_index; end;")
+	cancel()
+	if !errors.Is(err, ErrUniqueViolation) {
+		t.Errorf("exec error - received: %v - expected unique violation", err)
+	}
+}
+
+// TestSessionPool tests getting connections from an OCI session pool
+func TestSessionPool(t *testing.T) {
+	if TestDisableDatabase {
+		t.SkipNow()
+	}
+
+	t.Parallel()
+
+	const params = "?session_pool=true&pool_min=1&pool_max=4&pool_get_timeout=30s"
+	db := testGetDB(params)
+	if db == nil {
+		t.Fatal("db is null")
+	}
+
+	var waitGroup sync.WaitGroup
+	errs := make(chan error, 8)
+	for i := 0; i < 8; i++ {
+		waitGroup.Add(1)
+		go func(i int) {
+			defer waitGroup.Done()
+			var result int64
+			ctx, cancel := context.WithTimeout(context.Background(), TestContextTimeout)
+			err := db.QueryRowContext(ctx, "select :1 from dual", i).Scan(&result)
+			cancel()
+			if err != nil {
+				errs <- err
+				return
+			}
+			if result != int64(i) {
+				errs <- fmt.Errorf("received: %v - expected: %v", result, i)
+			}
+		}(i)
+	}
+	waitGroup.Wait()
+	close(errs)
+	for err := range errs {
+		t.Error("query error:", err)
+	}
+
+	// only the pinned connection keeps a session
+	ctx, cancel := context.WithTimeout(context.Background(), TestContextTimeout)
+	defer cancel()
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		t.Fatal("conn error:", err)
+	}
+	db.SetMaxIdleConns(0)
+
+	stats, err := GetSessionPoolStats(testGetDSN(params))
+	if err != nil {
+		t.Fatal("session pool stats error:", err)
+	}
+	if stats.Busy != 1 {
+		t.Errorf("busy - received: %v - expected: %v", stats.Busy, 1)
+	}
+	if stats.Open < 1 || stats.Open > 4 {
+		t.Errorf("open - received: %v - expected between 1 and 4", stats.Open)
+	}
+	if stats.Min != 1 || stats.Max != 4 || stats.Increment != 1 {
+		t.Errorf("stats - received: %+v - expected min 1, max 4, and increment 1", stats)
+	}
+
+	err = conn.Close()
+	if err != nil {
+		t.Fatal("conn close error:", err)
+	}
+	err = db.Close()
+	if err != nil {
+		t.Fatal("db close error:", err)
+	}
+
+	// the session pool is kept without connections
+	stats, err = GetSessionPoolStats(testGetDSN(params))
+	if err != nil {
+		t.Fatal("session pool stats error:", err)
+	}
+	if stats.Busy != 0 {
+		t.Errorf("busy - received: %v - expected: %v", stats.Busy, 0)
+	}
+
+	err = CloseSessionPool(testGetDSN(params))
+	if err != nil {
+		t.Fatal("close session pool error:", err)
+	}
+	_, err = GetSessionPoolStats(testGetDSN(params))
+	if err == nil {
+		t.Fatal("session pool stats - expected error")
+	}
+}
+
+// TestSessionPoolIdleTimeout tests a session pool without connections is destroyed after the pool idle timeout
+func TestSessionPoolIdleTimeout(t *testing.T) {
+	if TestDisableDatabase {
+		t.SkipNow()
+	}
+
+	t.Parallel()
+
+	const params = "?session_pool=true&pool_min=1&pool_max=2&pool_idle_timeout=1s"
+	db := testGetDB(params)
+	if db == nil {
+		t.Fatal("db is null")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), TestContextTimeout)
+	err := db.PingContext(ctx)
+	cancel()
+	if err != nil {
+		t.Fatal("ping error:", err)
+	}
+	err = db.Close()
+	if err != nil {
+		t.Fatal("db close error:", err)
+	}
+
+	_, err = GetSessionPoolStats(testGetDSN(params))
+	if err != nil {
+		t.Fatal("session pool stats error:", err)
+	}
+
+	time.Sleep(2 * time.Second)
+	_, err = GetSessionPoolStats(testGetDSN(params))
+	if err == nil {
+		t.Fatal("session pool stats - expected error")
+	}
+}
+
+// TestSessionPoolNoUsername tests a session pool without a username, which uses external credentials
+func TestSessionPoolNoUsername(t *testing.T) {
+	if TestDisableDatabase {
+		t.SkipNow()
+	}
+
+	t.Parallel()
+
+	dsn := TestHostValid + "?session_pool=true&pool_min=0&pool_max=2"
+	db, err := sql.Open("oci8", dsn)
+	if err != nil {
+		t.Fatal("open error:", err)
+	}
+
+	// fails unless external authentication is set up
+	ctx, cancel := context.WithTimeout(context.Background(), TestContextTimeout)
+	err = db.PingContext(ctx)
+	cancel()
+	if err != nil {
+		t.Log("ping error:", err)
+	}
+
+	err = db.Close()
+	if err != nil {
+		t.Fatal("db close error:", err)
+	}
+
+	// the pool is not created if OCISessionPoolCreate failed
+	CloseSessionPool(dsn)
+	_, err = GetSessionPoolStats(dsn)
+	if err == nil {
+		t.Fatal("session pool stats - expected error")
+	}
+}
+
+// TestStmtCache tests the statement cache hits and misses
+func TestStmtCache(t *testing.T) {
+	if TestDisableDatabase {
+		t.SkipNow()
+	}
+
+	t.Parallel()
+
+	db := testGetDB("?stmt_cache_size=10")
+	if db == nil {
+		t.Fatal("db is null")
+	}
+
+	defer func() {
+		err := db.Close()
+		if err != nil {
+			t.Fatal("db close error:", err)
+		}
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), TestContextTimeout)
+	defer cancel()
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		t.Fatal("conn error:", err)
+	}
+	defer conn.Close()
+
+	for i := 0; i < 3; i++ {
+		var result int64
+		err = conn.QueryRowContext(ctx, "select :1 from dual", i).Scan(&result)
+		if err != nil {
+			t.Fatal("query row error:", err)
+		}
+		if result != int64(i) {
+			t.Fatalf("result - received: %v - expected: %v", result, i)
+		}
+	}
+
+	var stats StmtCacheStats
+	err = conn.Raw(func(driverConn interface{}) error {
+		stats = driverConn.(*Conn).StmtCacheStats()
+		return nil
+	})
+	if err != nil {
+		t.Fatal("raw error:", err)
+	}
+	expected := StmtCacheStats{Size: 10, Hits: 2, Misses: 1}
+	if stats != expected {
+		t.Errorf("stmt cache stats - received: %+v - expected: %+v", stats, expected)
+	}
+}
+
+// TestContextCancelReset checks that a connection can be used after a call is canceled
+func TestContextCancelReset(t *testing.T) {
+	if TestDisableDatabase {
+		t.SkipNow()
+	}
+
+	t.Parallel()
+
+	db := testGetDB("")
+	if db == nil {
+		t.Fatal("db is null")
+	}
+
+	defer func() {
+		err := db.Close()
+		if err != nil {
+			t.Fatal("db close error:", err)
+		}
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), TestContextTimeout)
+	defer cancel()
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		t.Fatal("conn error:", err)
+	}
+	defer conn.Close()
+
+	for i := 0; i < 2; i++ {
+		// cancel without a deadline uses the call watcher
+		cancelCtx, cancelCancel := context.WithCancel(context.Background())
+		timer := time.AfterFunc(200*time.Millisecond, cancelCancel)
+		_, err = conn.ExecContext(cancelCtx, "begin SYS.DBMS_LOCK.SLEEP(1); end;")
+		timer.Stop()
+		cancelCancel()
+		expected := "ORA-01013"
+		if err == nil || len(err.Error()) < len(expected) || err.Error()[:len(expected)] != expected {
+			t.Fatalf("exec - expected: %v - received: %v", expected, err)
+		}
+
+		// the deadline uses the OCI call timeout when supported
+		timeoutCtx, timeoutCancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+		_, err = conn.ExecContext(timeoutCtx, "begin SYS.DBMS_LOCK.SLEEP(1); end;")
+		timeoutCancel()
+		if err == nil {
+			t.Fatal("exec - expected error")
+		}
+
+		// cancel before the deadline breaks the call even when the deadline is the OCI call timeout
+		timeoutCtx, timeoutCancel = context.WithTimeout(context.Background(), 10*time.Second)
+		timer = time.AfterFunc(200*time.Millisecond, timeoutCancel)
+		start := time.Now()
+		_, err = conn.ExecContext(timeoutCtx, "begin SYS.DBMS_LOCK.SLEEP(5); end;")
+		timer.Stop()
+		timeoutCancel()
+		if err == nil || len(err.Error()) < len(expected) || err.Error()[:len(expected)] != expected {
+			t.Fatalf("exec - expected: %v - received: %v", expected, err)
+		}
+		if time.Since(start) > 4*time.Second {
+			t.Fatalf("exec - canceled call took: %v", time.Since(start))
+		}
+
+		var result int64
+		err = conn.QueryRowContext(ctx, "select 1 from dual").Scan(&result)
+		if err != nil {
+			t.Fatal("query row error:", err)
+		}
+		if result != 1 {
+			t.Fatalf("result - received: %v - expected: %v", result, 1)
+		}
+	}
+}
+
+// TestResetSession checks that ResetSession rolls back a leaked transaction and that bad connections are not reused
+func TestResetSession(t *testing.T) {
+	if TestDisableDatabase {
+		t.SkipNow()
+	}
+
+	t.Parallel()
+
+	db := testGetDB("?reset_package=true")
+	if db == nil {
+		t.Fatal("db is null")
+	}
+
+	defer func() {
+		err := db.Close()
+		if err != nil {
+			t.Fatal("db close error:", err)
+		}
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), TestContextTimeout)
+	defer cancel()
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		t.Fatal("conn error:", err)
+	}
+
+	err = conn.Raw(func(driverConn interface{}) error {
+		oci8Conn := driverConn.(*Conn)
+		oci8Conn.inTransaction = true
+		err := oci8Conn.ResetSession(ctx)
+		if err != nil {
+			return fmt.Errorf("reset session error: %v", err)
+		}
+		if oci8Conn.inTransaction {
+			return fmt.Errorf("in transaction after reset session")
+		}
+		if !oci8Conn.IsValid() {
+			return fmt.Errorf("connection not valid after reset session")
+		}
+
+		oci8Conn.badConn = true
+		if oci8Conn.IsValid() {
+			return fmt.Errorf("bad connection is valid")
+		}
+		err = oci8Conn.ResetSession(ctx)
+		if err != driver.ErrBadConn {
+			return fmt.Errorf("reset session - received: %v - expected: %v", err, driver.ErrBadConn)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = conn.Close()
+	if err != nil {
+		t.Fatal("conn close error:", err)
+	}
+
+	// the bad connection was discarded
+	stats := db.Stats()
+	if stats.OpenConnections != 0 {
+		t.Fatalf("open connections - received: %v - expected: %v", stats.OpenConnections, 0)
+	}
+}
+
+// TestTxOptions checks transaction isolation levels and read only transactions
+func TestTxOptions(t *testing.T) {
+	if TestDisableDatabase || TestDisableDestructive {
+		t.SkipNow()
+	}
+
+	tableName := "TX_OPTIONS_" + TestTimeString
+	err := testExec(t, "create table "+tableName+" ( A INT )", nil)
+	if err != nil {
+		t.Fatal("create table error:", err)
+	}
+	defer testDropTable(t, tableName)
+
+	ctx, cancel := context.WithTimeout(context.Background(), TestContextTimeout)
+	defer cancel()
+
+	// read only
+	tx, err := TestDB.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		t.Fatal("begin tx error:", err)
+	}
+	_, err = tx.ExecContext(ctx, "insert into "+tableName+" ( A ) values (1)")
+	expected := "ORA-01456"
+	if err == nil || !strings.HasPrefix(err.Error(), expected) {
+		t.Errorf("read only insert - expected: %v - received: %v", expected, err)
+	}
+	err = tx.Rollback()
+	if err != nil {
+		t.Fatal("rollback error:", err)
+	}
+
+	// serializable, then read committed on the same connection
+	isolationLevels := []sql.IsolationLevel{sql.LevelSerializable, sql.LevelReadCommitted}
+	for i, isolationLevel := range isolationLevels {
+		tx, err = TestDB.BeginTx(ctx, &sql.TxOptions{Isolation: isolationLevel})
+		if err != nil {
+			t.Fatalf("begin tx %v error: %v", isolationLevel, err)
+		}
+		_, err = tx.ExecContext(ctx, "insert into "+tableName+" ( A ) values (:1)", i)
+		if err != nil {
+			t.Fatalf("insert %v error: %v", isolationLevel, err)
+		}
+		var isolation string
+		err = tx.QueryRowContext(ctx, "select case bitand(t.flag, 268435456) when 0 then 'READ COMMITTED' else 'SERIALIZABLE' end"+
+			" from v$transaction t, v$session s where t.addr = s.taddr and s.sid = sys_context('USERENV', 'SID')").Scan(&isolation)
+		if err != nil {
+			t.Logf("isolation query %v error: %v", isolationLevel, err)
+		} else if isolation != strings.ToUpper(isolationLevel.String()) {
+			t.Errorf("isolation - received: %v - expected: %v", isolation, strings.ToUpper(isolationLevel.String()))
+		}
+		err = tx.Commit()
+		if err != nil {
+			t.Fatalf("commit %v error: %v", isolationLevel, err)
+		}
+	}
+
+	// unsupported
+	unsupported := []sql.TxOptions{
+		{Isolation: sql.LevelReadUncommitted},
+		{Isolation: sql.LevelRepeatableRead},
+		{Isolation: sql.LevelSnapshot},
+		{Isolation: sql.LevelLinearizable},
+		{Isolation: sql.LevelReadCommitted, ReadOnly: true},
+	}
+	for _, txOptions := range unsupported {
+		txOptions := txOptions
+		tx, err = TestDB.BeginTx(ctx, &txOptions)
+		if err == nil {
+			tx.Rollback()
+			t.Errorf("begin tx %+v - expected error", txOptions)
+		}
+	}
+}
+
+// TestSavepoint checks savepoints in a transaction
+func TestSavepoint(t *testing.T) {
+	if TestDisableDatabase || TestDisableDestructive {
+		t.SkipNow()
+	}
+
+	tableName := "SAVEPOINT_" + TestTimeString
+	err := testExec(t, "create table "+tableName+" ( A INT )", nil)
+	if err != nil {
+		t.Fatal("create table error:", err)
+	}
+	defer testDropTable(t, tableName)
+
+	ctx, cancel := context.WithTimeout(context.Background(), TestContextTimeout)
+	defer cancel()
+	conn, err := TestDB.Conn(ctx)
+	if err != nil {
+		t.Fatal("conn error:", err)
+	}
+	defer conn.Close()
+
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		t.Fatal("begin tx error:", err)
+	}
+
+	savepoint := func(f func(oci8Tx *Tx) error) {
+		err := conn.Raw(func(driverConn interface{}) error {
+			oci8Tx := driverConn.(*Conn).CurrentTx()
+			if oci8Tx == nil {
+				return fmt.Errorf("no transaction")
+			}
+			return f(oci8Tx)
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for i := 1; i <= 3; i++ {
+		_, err = tx.ExecContext(ctx, "insert into "+tableName+" ( A ) values (:1)", i)
+		if err != nil {
+			t.Fatal("insert error:", err)
+		}
+		savepoint(func(oci8Tx *Tx) error { return oci8Tx.Savepoint("sp" + strconv.Itoa(i)) })
+	}
+
+	savepoint(func(oci8Tx *Tx) error {
+		err := oci8Tx.RollbackTo("sp1")
+		if err != nil {
+			return err
+		}
+		if !reflect.DeepEqual(oci8Tx.Savepoints(), []string{"SP1"}) {
+			return fmt.Errorf("savepoints - received: %v - expected: %v", oci8Tx.Savepoints(), []string{"SP1"})
+		}
+		err = oci8Tx.RollbackTo("sp2")
+		if err == nil {
+			return fmt.Errorf("rollback to removed savepoint - expected error")
+		}
+		return nil
+	})
+
+	var count int64
+	err = tx.QueryRowContext(ctx, "select count(1) from "+tableName).Scan(&count)
+	if err != nil {
+		t.Fatal("query row error:", err)
+	}
+	if count != 1 {
+		t.Fatalf("count - received: %v - expected: %v", count, 1)
+	}
+
+	savepoint(func(oci8Tx *Tx) error { return oci8Tx.Release("sp1") })
+
+	err = tx.Commit()
+	if err != nil {
+		t.Fatal("commit error:", err)
+	}
+
+	err = conn.Raw(func(driverConn interface{}) error {
+		if driverConn.(*Conn).CurrentTx() != nil {
+			return fmt.Errorf("transaction after commit")
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+// TestXA checks two-phase commit transaction branches
+func TestXA(t *testing.T) {
+	if TestDisableDatabase || TestDisableDestructive {
+		t.SkipNow()
+	}
+
+	tableName := "XA_" + TestTimeString
+	err := testExec(t, "create table "+tableName+" ( A INT )", nil)
+	if err != nil {
+		t.Fatal("create table error:", err)
+	}
+	defer testDropTable(t, tableName)
+
+	ctx, cancel := context.WithTimeout(context.Background(), TestContextTimeout)
+	defer cancel()
+
+	conn1, err := TestDB.Conn(ctx)
+	if err != nil {
+		t.Fatal("conn error:", err)
+	}
+	defer conn1.Close()
+	conn2, err := TestDB.Conn(ctx)
+	if err != nil {
+		t.Fatal("conn error:", err)
+	}
+	defer conn2.Close()
+
+	raw := func(conn *sql.Conn, f func(oci8Conn *Conn) error) {
+		err := conn.Raw(func(driverConn interface{}) error {
+			return f(driverConn.(*Conn))
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// prepare and commit
+	xid1 := XID{FormatID: 0x6f6369, GlobalTransactionID: []byte("TestXA1-" + TestTimeString), BranchQualifier: []byte("1")}
+	tx, err := conn1.BeginTx(WithXID(ctx, xid1, time.Minute), nil)
+	if err != nil {
+		t.Fatal("begin tx error:", err)
+	}
+	_, err = tx.ExecContext(ctx, "insert into "+tableName+" ( A ) values (1)")
+	if err != nil {
+		t.Fatal("insert error:", err)
+	}
+	raw(conn1, func(oci8Conn *Conn) error {
+		readOnly, err := oci8Conn.CurrentTx().Prepare()
+		if err != nil {
+			return err
+		}
+		if readOnly {
+			return fmt.Errorf("prepare - received read only")
+		}
+		return nil
+	})
+	err = tx.Commit()
+	if err != nil {
+		t.Fatal("commit error:", err)
+	}
+
+	// detach in one session, resume and prepare in another session, then commit by XID in the first session
+	xid2 := XID{FormatID: 0x6f6369, GlobalTransactionID: []byte("TestXA2-" + TestTimeString), BranchQualifier: []byte("1")}
+	tx, err = conn1.BeginTx(WithXID(ctx, xid2, time.Minute), nil)
+	if err != nil {
+		t.Fatal("begin tx error:", err)
+	}
+	_, err = tx.ExecContext(ctx, "insert into "+tableName+" ( A ) values (2)")
+	if err != nil {
+		t.Fatal("insert error:", err)
+	}
+	raw(conn1, func(oci8Conn *Conn) error { return oci8Conn.CurrentTx().Detach() })
+	err = tx.Rollback()
+	if err != nil {
+		t.Fatal("rollback detached error:", err)
+	}
+
+	tx, err = conn2.BeginTx(WithXIDResume(ctx, xid2, 5*time.Second), nil)
+	if err != nil {
+		t.Fatal("resume tx error:", err)
+	}
+	raw(conn2, func(oci8Conn *Conn) error {
+		_, err := oci8Conn.CurrentTx().Prepare()
+		if err != nil {
+			return err
+		}
+		return oci8Conn.CurrentTx().Detach()
+	})
+	err = tx.Rollback()
+	if err != nil {
+		t.Fatal("rollback detached error:", err)
+	}
+
+	raw(conn1, func(oci8Conn *Conn) error { return oci8Conn.CommitXID(xid2) })
+
+	// read only branch
+	xid3 := XID{FormatID: 0x6f6369, GlobalTransactionID: []byte("TestXA3-" + TestTimeString), BranchQualifier: []byte("1")}
+	tx, err = conn1.BeginTx(WithXID(ctx, xid3, time.Minute), nil)
+	if err != nil {
+		t.Fatal("begin tx error:", err)
+	}
+	raw(conn1, func(oci8Conn *Conn) error {
+		readOnly, err := oci8Conn.CurrentTx().Prepare()
+		if err != nil {
+			return err
+		}
+		if !readOnly {
+			return fmt.Errorf("prepare - expected read only")
+		}
+		return nil
+	})
+	err = tx.Commit()
+	if err != nil {
+		t.Fatal("commit read only error:", err)
+	}
+
+	var count int64
+	err = TestDB.QueryRowContext(ctx, "select count(1) from "+tableName).Scan(&count)
+	if err != nil {
+		t.Fatal("query row error:", err)
+	}
+	if count != 2 {
+		t.Fatalf("count - received: %v - expected: %v", count, 2)
+	}
+}
+
+// TestLTXID checks getting the commit outcome of a logical transaction
+func TestLTXID(t *testing.T) {
+	if TestDisableDatabase || TestDisableDestructive {
+		t.SkipNow()
+	}
+
+	tableName := "LTXID_" + TestTimeString
+	err := testExec(t, "create table "+tableName+" ( A INT )", nil)
+	if err != nil {
+		t.Fatal("create table error:", err)
+	}
+	defer testDropTable(t, tableName)
+
+	ctx, cancel := context.WithTimeout(context.Background(), TestContextTimeout)
+	defer cancel()
+	conn, err := TestDB.Conn(ctx)
+	if err != nil {
+		t.Fatal("conn error:", err)
+	}
+	defer conn.Close()
+
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		t.Fatal("begin tx error:", err)
+	}
+	_, err = tx.ExecContext(ctx, "insert into "+tableName+" ( A ) values (1)")
+	if err != nil {
+		t.Fatal("insert error:", err)
+	}
+
+	var ltxid []byte
+	err = conn.Raw(func(driverConn interface{}) error {
+		var err error
+		ltxid, err = driverConn.(*Conn).LTXID()
+		return err
+	})
+	if err != nil {
+		tx.Rollback()
+		t.Skip("LTXID error, Transaction Guard may not be enabled for the service:", err)
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		t.Fatal("commit error:", err)
+	}
+
+	outcome, err := GetLTXIDOutcome(ctx, TestDB, ltxid)
+	if err != nil {
+		t.Fatal("get LTXID outcome error:", err)
+	}
+	if !outcome.Committed || !outcome.UserCallCompleted {
+		t.Fatalf("outcome - received: %+v - expected committed and user call completed", outcome)
+	}
+}
+
+// TestImplicitResults checks implicit result sets returned by DBMS_SQL.RETURN_RESULT
+func TestImplicitResults(t *testing.T) {
+	if TestDisableDatabase {
+		t.SkipNow()
+	}
+
+	t.Parallel()
+
+	ctx, cancel := context.WithTimeout(context.Background(), TestContextTimeout)
+	defer cancel()
+	rows, err := TestDB.QueryContext(ctx, `
+declare
+  c1 sys_refcursor;
+  c2 sys_refcursor;
+begin
+  open c1 for select level as A from dual connect by level <= 3;
+  DBMS_SQL.RETURN_RESULT(c1);
+  open c2 for select 'a' as B, 'b' as C from dual;
+  DBMS_SQL.RETURN_RESULT(c2);
+end;`)
+	if err != nil {
+		t.Fatal("query error:", err)
+	}
+	defer rows.Close()
+
+	var aInts []int64
+	for rows.Next() {
+		var aInt int64
+		err = rows.Scan(&aInt)
+		if err != nil {
+			t.Fatal("scan error:", err)
+		}
+		aInts = append(aInts, aInt)
+	}
+	if !reflect.DeepEqual(aInts, []int64{1, 2, 3}) {
+		t.Fatalf("first result - received: %v - expected: %v", aInts, []int64{1, 2, 3})
+	}
+
+	if !rows.NextResultSet() {
+		t.Fatal("next result set - expected true, error:", rows.Err())
+	}
+	columns, err := rows.Columns()
+	if err != nil {
+		t.Fatal("columns error:", err)
+	}
+	if !reflect.DeepEqual(columns, []string{"B", "C"}) {
+		t.Fatalf("columns - received: %v - expected: %v", columns, []string{"B", "C"})
+	}
+	if !rows.Next() {
+		t.Fatal("next - expected true, error:", rows.Err())
+	}
+	var b, c string
+	err = rows.Scan(&b, &c)
+	if err != nil {
+		t.Fatal("scan error:", err)
+	}
+	if b != "a" || c != "b" {
+		t.Fatalf("second result - received: %v %v - expected: a b", b, c)
+	}
+	if rows.Next() {
+		t.Fatal("next - expected false")
+	}
+
+	if rows.NextResultSet() {
+		t.Fatal("next result set - expected false")
+	}
+	err = rows.Err()
+	if err != nil {
+		t.Fatal("rows error:", err)
+	}
+}
+
+// TestRefCursorOut checks a ref cursor out bind
+func TestRefCursorOut(t *testing.T) {
+	if TestDisableDatabase {
+		t.SkipNow()
+	}
+
+	t.Parallel()
+
+	ctx, cancel := context.WithTimeout(context.Background(), TestContextTimeout)
+	defer cancel()
+
+	// the cursor uses the connection, so keep the connection until the cursor is closed
+	conn, err := TestDB.Conn(ctx)
+	if err != nil {
+		t.Fatal("conn error:", err)
+	}
+	defer conn.Close()
+
+	var cursor driver.Rows
+	_, err = conn.ExecContext(ctx, "begin open :1 for select level as A, 'row ' || level as B from dual connect by level <= 3; end;",
+		sql.Out{Dest: &cursor})
+	if err != nil {
+		t.Fatal("exec error:", err)
+	}
+	if cursor == nil {
+		t.Fatal("cursor is nil")
+	}
+
+	columns := cursor.Columns()
+	if !reflect.DeepEqual(columns, []string{"A", "B"}) {
+		t.Fatalf("columns - received: %v - expected: %v", columns, []string{"A", "B"})
+	}
+
+	var results [][]driver.Value
+	for {
+		values := make([]driver.Value, len(columns))
+		err = cursor.Next(values)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal("next error:", err)
+		}
+		results = append(results, values)
+	}
+	expected := [][]driver.Value{{float64(1), "row 1"}, {float64(2), "row 2"}, {float64(3), "row 3"}}
+	if !reflect.DeepEqual(results, expected) {
+		t.Fatalf("results - received: %v - expected: %v", results, expected)
+	}
+
+	err = cursor.Close()
+	if err != nil {
+		t.Fatal("cursor close error:", err)
+	}
+
+	// the cursor can be fetched after the exec context is done
+	execCtx, execCancel := context.WithTimeout(context.Background(), TestContextTimeout)
+	_, err = conn.ExecContext(execCtx, "begin open :1 for select 1 as A from dual; end;", sql.Out{Dest: &cursor})
+	execCancel()
+	if err != nil {
+		t.Fatal("exec error:", err)
+	}
+	values := make([]driver.Value, 1)
+	err = cursor.Next(values)
+	cursor.Close()
+	if err != nil {
+		t.Fatal("next error:", err)
+	}
+	if values[0] != float64(1) {
+		t.Fatalf("value - received: %v - expected: %v", values[0], 1)
+	}
+
+	// cursor not opened is nil
+	_, err = conn.ExecContext(ctx, "declare c sys_refcursor; begin :1 := c; end;", sql.Out{Dest: &cursor})
+	if err != nil {
+		t.Fatal("exec error:", err)
+	}
+	if cursor != nil {
+		cursor.Close()
+		t.Fatal("cursor - expected nil")
+	}
+}
+
+// TestFetchArraySize checks fetching many rows with fetch array size
+func TestFetchArraySize(t *testing.T) {
+	if TestDisableDatabase {
+		t.SkipNow()
+	}
+
+	t.Parallel()
+
+	for _, fetchArraySize := range []int{1, 7, 100} {
+		db := testGetDB("?fetch_array_size=" + strconv.Itoa(fetchArraySize))
+		if db == nil {
+			t.Fatal("db is null")
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), TestContextTimeout)
+		rows, err := db.QueryContext(ctx, "select level, case when mod(level, 3) = 0 then null else to_char(level) end from dual connect by level <= 100")
+		if err != nil {
+			cancel()
+			db.Close()
+			t.Fatal("query error:", err)
+		}
+
+		var count int64
+		for rows.Next() {
+			var aInt int64
+			var aString sql.NullString
+			err = rows.Scan(&aInt, &aString)
+			if err != nil {
+				t.Fatal("scan error:", err)
+			}
+			count++
+			if aInt != count {
+				t.Fatalf("fetch array size %v - received: %v - expected: %v", fetchArraySize, aInt, count)
+			}
+			if aString.Valid != (count%3 != 0) || (aString.Valid && aString.String != strconv.FormatInt(count, 10)) {
+				t.Fatalf("fetch array size %v - received: %v - expected: %v", fetchArraySize, aString, count)
+			}
+		}
+		err = rows.Err()
+		if err != nil {
+			t.Fatal("rows error:", err)
+		}
+		err = rows.Close()
+		cancel()
+		if err != nil {
+			t.Fatal("rows close error:", err)
+		}
+
+		if count != 100 {
+			t.Fatalf("fetch array size %v - count received: %v - expected: %v", fetchArraySize, count, 100)
+		}
+
+		err = db.Close()
+		if err != nil {
+			t.Fatal("db close error:", err)
+		}
+	}
+}
+
 func BenchmarkSimpleInsert(b *testing.B) {
 	if TestDisableDatabase || TestDisableDestructive {
 		b.SkipNow()
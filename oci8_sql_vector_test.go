@@ -0,0 +1,133 @@
+package oci8
+
+import (
+	"context"
+	"database/sql"
+	"reflect"
+	"testing"
+)
+
+// TestDestructiveVector checks fetching and binding VECTOR columns
+func TestDestructiveVector(t *testing.T) {
+	if TestDisableDatabase || TestDisableDestructive {
+		t.SkipNow()
+	}
+
+	var serverVersion int64
+	ctx, cancel := context.WithTimeout(context.Background(), TestContextTimeout)
+	_, err := TestDB.ExecContext(ctx, "begin :1 := dbms_db_version.version; end;", sql.Out{Dest: &serverVersion})
+	cancel()
+	if err != nil {
+		t.Fatal("exec error:", err)
+	}
+	if clientVersionMajor < 23 || serverVersion < 23 {
+		t.Skip("VECTOR type needs Oracle 23 or later")
+	}
+
+	tableName := "VECTOR_" + TestTimeString
+	err = testExec(t, "create table "+tableName+" ( A INTEGER, B VECTOR(3, FLOAT32), C VECTOR(3, FLOAT64), D VECTOR(3, INT8), "+
+		"E VECTOR(16, BINARY), F VECTOR(5, FLOAT32, SPARSE), G VECTOR(*, *) )", nil)
+	if err != nil {
+		t.Fatal("create table error:", err)
+	}
+	defer testDropTable(t, tableName)
+
+	float32s := []float32{1.5, -2, 3.25}
+	float64s := []float64{1.125, 2, -3}
+	int8s := []int8{-128, 0, 127}
+	binary := []byte{0x0f, 0xf0}
+	sparse := SparseVector{Dimensions: 5, Indices: []uint32{1, 4}, Values: []float32{0.5, -1}}
+
+	// insert
+	ctx, cancel = context.WithTimeout(context.Background(), TestContextTimeout)
+	_, err = TestDB.ExecContext(ctx, "insert into "+tableName+" ( A, B, C, D, E, F, G ) values (1, :1, :2, :3, :4, :5, :6)",
+		Vector{Values: float32s}, Vector{Values: float64s}, Vector{Values: int8s}, Vector{Values: binary}, sparse, Vector{Values: float64s})
+	cancel()
+	if err != nil {
+		t.Fatal("insert error:", err)
+	}
+
+	ctx, cancel = context.WithTimeout(context.Background(), TestContextTimeout)
+	_, err = TestDB.ExecContext(ctx, "insert into "+tableName+" ( A, B, F ) values (2, :1, :2)", Vector{}, SparseVector{})
+	cancel()
+	if err != nil {
+		t.Fatal("insert error:", err)
+	}
+
+	// select
+	ctx, cancel = context.WithTimeout(context.Background(), TestContextTimeout)
+	defer cancel()
+	rows, err := TestDB.QueryContext(ctx, "select B, C, D, E, F, G from "+tableName+" where A = 1")
+	if err != nil {
+		t.Fatal("query error:", err)
+	}
+	defer rows.Close()
+
+	columnTypes, err := rows.ColumnTypes()
+	if err != nil {
+		t.Fatal("column types error:", err)
+	}
+	expectedTypes := []reflect.Type{typeSliceFloat32, typeSliceFloat64, typeSliceInt8, typeSliceByte, typeSparseVector, typeInterface}
+	for i, columnType := range columnTypes {
+		if columnType.ScanType() != expectedTypes[i] {
+			t.Errorf("column %v scan type - received: %v - expected: %v", i, columnType.ScanType(), expectedTypes[i])
+		}
+	}
+
+	if !rows.Next() {
+		t.Fatal("no rows:", rows.Err())
+	}
+	var receivedFloat32s []float32
+	var receivedFloat64s []float64
+	var receivedInt8s []int8
+	var receivedBinary []byte
+	var receivedSparse SparseVector
+	var receivedFlexible interface{}
+	err = rows.Scan(&receivedFloat32s, &receivedFloat64s, &receivedInt8s, &receivedBinary, &receivedSparse, &receivedFlexible)
+	if err != nil {
+		t.Fatal("scan error:", err)
+	}
+	for i, test := range []struct {
+		received interface{}
+		expected interface{}
+	}{
+		{receivedFloat32s, float32s},
+		{receivedFloat64s, float64s},
+		{receivedInt8s, int8s},
+		{receivedBinary, binary},
+		{receivedSparse, sparse},
+		{receivedFlexible, float64s},
+	} {
+		if !reflect.DeepEqual(test.received, test.expected) {
+			t.Errorf("column %v - received: %v - expected: %v", i, test.received, test.expected)
+		}
+	}
+
+	// null
+	ctx, cancel = context.WithTimeout(context.Background(), TestContextTimeout)
+	err = TestDB.QueryRowContext(ctx, "select B, F from "+tableName+" where A = 2").Scan(&receivedFloat32s, &receivedFlexible)
+	cancel()
+	if err != nil {
+		t.Fatal("scan error:", err)
+	}
+	if receivedFloat32s != nil || receivedFlexible != nil {
+		t.Fatalf("null - received: %v, %v", receivedFloat32s, receivedFlexible)
+	}
+
+	// out bind
+	var out Vector
+	var outSparse SparseVector
+	ctx, cancel = context.WithTimeout(context.Background(), TestContextTimeout)
+	_, err = TestDB.ExecContext(ctx, "begin select D, F into :1, :2 from "+tableName+" where A = 1; end;",
+		sql.Out{Dest: &out}, sql.Out{Dest: &outSparse})
+	cancel()
+	if err != nil {
+		t.Fatal("out error:", err)
+	}
+	if !reflect.DeepEqual(out.Values, int8s) {
+		t.Fatalf("out - received: %v - expected: %v", out.Values, int8s)
+	}
+	if !reflect.DeepEqual(outSparse, sparse) {
+		t.Fatalf("out sparse - received: %v - expected: %v", outSparse, sparse)
+	}
+}
@@ -0,0 +1,114 @@
+package oci8
+
+import (
+	"context"
+	"io/ioutil"
+	"strings"
+	"testing"
+)
+
+// TestXMLTypeScan checks scanning into XMLType and reading the document
+func TestXMLTypeScan(t *testing.T) {
+	t.Parallel()
+
+	var xmlType XMLType
+	for _, src := range []interface{}{"<a>1</a>", []byte("<a>1</a>")} {
+		err := xmlType.Scan(src)
+		if err != nil {
+			t.Fatal("scan error:", err)
+		}
+		if xmlType.String() != "<a>1</a>" {
+			t.Fatalf("scan %T - received: %v", src, xmlType.String())
+		}
+		data, err := ioutil.ReadAll(xmlType.Reader())
+		if err != nil {
+			t.Fatal("read error:", err)
+		}
+		if string(data) != "<a>1</a>" {
+			t.Fatalf("reader %T - received: %s", src, data)
+		}
+	}
+
+	err := xmlType.Scan(nil)
+	if err != nil {
+		t.Fatal("scan error:", err)
+	}
+	if xmlType.Document != nil {
+		t.Fatalf("scan nil - received: %v", xmlType.Document)
+	}
+
+	err = xmlType.Scan(int64(1))
+	if err == nil {
+		t.Fatal("scan int64 - expected error")
+	}
+}
+
+// TestDestructiveXMLType checks fetching and binding XMLType columns
+func TestDestructiveXMLType(t *testing.T) {
+	if TestDisableDatabase || TestDisableDestructive {
+		t.SkipNow()
+	}
+
+	tableName := "XMLTYPE_" + TestTimeString
+	err := testExec(t, "create table "+tableName+" ( A INTEGER, B XMLTYPE ) xmltype column B store as clob", nil)
+	if err != nil {
+		t.Fatal("create table error:", err)
+	}
+	defer testDropTable(t, tableName)
+
+	large := "<root>" + strings.Repeat("<item>abcdefghijklmnopqrstuvwxyz</item>", 2000) + "</root>"
+	documents := []struct {
+		id       int64
+		value    interface{}
+		expected string
+	}{
+		{1, XMLType{Document: "<a>1</a>"}, "<a>1</a>"},
+		{2, XMLType{Document: []byte("<b>2</b>")}, "<b>2</b>"},
+		{3, XMLType{Document: large}, large},
+		{4, XMLType{}, ""},
+		{5, "<c>5</c>", "<c>5</c>"},
+	}
+
+	for _, document := range documents {
+		ctx, cancel := context.WithTimeout(context.Background(), TestContextTimeout)
+		_, err = TestDB.ExecContext(ctx, "insert into "+tableName+" ( A, B ) values (:1, :2)", document.id, document.value)
+		cancel()
+		if err != nil {
+			t.Fatalf("insert %v error: %v", document.id, err)
+		}
+	}
+
+	for _, document := range documents {
+		var xmlType XMLType
+		ctx, cancel := context.WithTimeout(context.Background(), TestContextTimeout)
+		err = TestDB.QueryRowContext(ctx, "select B from "+tableName+" where A = :1", document.id).Scan(&xmlType)
+		cancel()
+		if err != nil {
+			t.Fatalf("scan %v error: %v", document.id, err)
+		}
+		if document.expected == "" {
+			if xmlType.Document != nil {
+				t.Fatalf("null - received: %v", xmlType.Document)
+			}
+			continue
+		}
+		data, err := ioutil.ReadAll(xmlType.Reader())
+		if err != nil {
+			t.Fatal("read error:", err)
+		}
+		if string(data) != document.expected {
+			t.Fatalf("select %v - received: %.100s - expected: %.100s", document.id, data, document.expected)
+		}
+
+		var received string
+		ctx, cancel = context.WithTimeout(context.Background(), TestContextTimeout)
+		err = TestDB.QueryRowContext(ctx, "select B from "+tableName+" where A = :1", document.id).Scan(&received)
+		cancel()
+		if err != nil {
+			t.Fatalf("scan %v error: %v", document.id, err)
+		}
+		if received != document.expected {
+			t.Fatalf("select string %v - received: %.100s - expected: %.100s", document.id, received, document.expected)
+		}
+	}
+}
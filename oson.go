@@ -0,0 +1,1047 @@
+package oci8
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"reflect"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// OSON is the Oracle binary JSON format of JSON columns.
+// The image is a header, the field names segment, then the tree segment of nodes.
+// Container nodes have the offsets of their children in the tree segment, objects also have the field ids of their children.
+
+const (
+	osonMagic1 = 0xff
+	osonMagic2 = 0x4a // J
+	osonMagic3 = 0x5a // Z
+
+	osonVersionMaxFieldName255   = 1
+	osonVersionMaxFieldName65535 = 3
+
+	// primary flags
+	osonFlagRelativeOffsets     = 0x0001
+	osonFlagInlineLeaf          = 0x0002
+	osonFlagNumFieldNamesUint32 = 0x0008
+	osonFlagIsScalar            = 0x0010
+	osonFlagHashIDUint8         = 0x0100
+	osonFlagNumFieldNamesUint16 = 0x0400
+	osonFlagFieldNamesSegUint32 = 0x0800
+	osonFlagTreeSegUint32       = 0x1000
+	osonFlagTinyNodesStat       = 0x2000
+
+	// secondary flags
+	osonFlagSecFieldNamesSegUint16 = 0x0100
+
+	// node types
+	osonTypeNull             = 0x30
+	osonTypeTrue             = 0x31
+	osonTypeFalse            = 0x32
+	osonTypeStringLength8    = 0x33
+	osonTypeNumberLength8    = 0x34
+	osonTypeBinaryDouble     = 0x36
+	osonTypeStringLength16   = 0x37
+	osonTypeStringLength32   = 0x38
+	osonTypeTimestamp        = 0x39
+	osonTypeBinaryLength16   = 0x3a
+	osonTypeBinaryLength32   = 0x3b
+	osonTypeDate             = 0x3c
+	osonTypeIntervalYM       = 0x3d
+	osonTypeIntervalDS       = 0x3e
+	osonTypeTimestampTZ      = 0x7c
+	osonTypeTimestamp7       = 0x7d
+	osonTypeID               = 0x7e
+	osonTypeBinaryFloat      = 0x7f
+	osonTypeObject           = 0x84
+	osonTypeArray            = 0xc0
+	osonContainerOffsets32   = 0x20
+	osonContainerChildren16  = 0x08
+	osonContainerChildren32  = 0x10
+	osonContainerSharedField = 0x18
+)
+
+var errOSONTruncated = errors.New("OSON data is truncated")
+
+// osonDecoder decodes an OSON image
+type osonDecoder struct {
+	data            []byte
+	pos             int
+	location        *time.Location
+	relativeOffsets bool
+	fieldIDLength   int
+	fieldNames      []string
+	treeSegmentPos  int
+}
+
+// decodeOSON decodes an OSON image to map[string]interface{} for objects, []interface{} for arrays, string, bool,
+// json.Number for numbers, float64 for binary floats and doubles, time.Time for dates and timestamps,
+// time.Duration for day to second intervals, []byte for binary values, or nil.
+// Dates and timestamps without a time zone are in location.
+func decodeOSON(data []byte, location *time.Location) (interface{}, error) {
+	decoder := &osonDecoder{data: data, location: location}
+	return decoder.decode()
+}
+
+// decode decodes the header, the field names, then the root node
+func (decoder *osonDecoder) decode() (interface{}, error) {
+	magic, err := decoder.readBytes(3)
+	if err != nil {
+		return nil, err
+	}
+	if magic[0] != osonMagic1 || magic[1] != osonMagic2 || magic[2] != osonMagic3 {
+		return nil, errors.New("OSON data has invalid magic bytes")
+	}
+	version, err := decoder.readUint8()
+	if err != nil {
+		return nil, err
+	}
+	if version != osonVersionMaxFieldName255 && version != osonVersionMaxFieldName65535 {
+		return nil, fmt.Errorf("OSON version %v is not supported", version)
+	}
+	flags, err := decoder.readUint16()
+	if err != nil {
+		return nil, err
+	}
+	decoder.relativeOffsets = flags&osonFlagRelativeOffsets != 0
+
+	if flags&osonFlagIsScalar != 0 {
+		// tree segment size
+		if flags&osonFlagTreeSegUint32 != 0 {
+			_, err = decoder.readBytes(4)
+		} else {
+			_, err = decoder.readBytes(2)
+		}
+		if err != nil {
+			return nil, err
+		}
+		decoder.treeSegmentPos = decoder.pos
+		return decoder.decodeNode()
+	}
+
+	var shortCount uint32
+	switch {
+	case flags&osonFlagNumFieldNamesUint32 != 0:
+		shortCount, err = decoder.readUint32()
+		decoder.fieldIDLength = 4
+	case flags&osonFlagNumFieldNamesUint16 != 0:
+		var count uint16
+		count, err = decoder.readUint16()
+		shortCount = uint32(count)
+		decoder.fieldIDLength = 2
+	default:
+		var count uint8
+		count, err = decoder.readUint8()
+		shortCount = uint32(count)
+		decoder.fieldIDLength = 1
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	shortOffsetSize := 2
+	var shortSegmentSize uint32
+	if flags&osonFlagFieldNamesSegUint32 != 0 {
+		shortOffsetSize = 4
+		shortSegmentSize, err = decoder.readUint32()
+	} else {
+		var size uint16
+		size, err = decoder.readUint16()
+		shortSegmentSize = uint32(size)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var longCount, longSegmentSize uint32
+	longOffsetSize := 4
+	if version == osonVersionMaxFieldName65535 {
+		var secondaryFlags uint16
+		secondaryFlags, err = decoder.readUint16()
+		if err != nil {
+			return nil, err
+		}
+		if secondaryFlags&osonFlagSecFieldNamesSegUint16 != 0 {
+			longOffsetSize = 2
+		}
+		longCount, err = decoder.readUint32()
+		if err != nil {
+			return nil, err
+		}
+		longSegmentSize, err = decoder.readUint32()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// tree segment size
+	if flags&osonFlagTreeSegUint32 != 0 {
+		_, err = decoder.readBytes(4)
+	} else {
+		_, err = decoder.readBytes(2)
+	}
+	if err != nil {
+		return nil, err
+	}
+	// number of tiny nodes
+	_, err = decoder.readBytes(2)
+	if err != nil {
+		return nil, err
+	}
+
+	err = decoder.readFieldNames(int(shortCount), 1, shortOffsetSize, int(shortSegmentSize), 1)
+	if err != nil {
+		return nil, err
+	}
+	err = decoder.readFieldNames(int(longCount), 2, longOffsetSize, int(longSegmentSize), 2)
+	if err != nil {
+		return nil, err
+	}
+
+	decoder.treeSegmentPos = decoder.pos
+	return decoder.decodeNode()
+}
+
+// readFieldNames reads a field names segment: the hash ids, the offsets of the names, then the names.
+// Each name is its length followed by the UTF-8 bytes.
+func (decoder *osonDecoder) readFieldNames(count int, hashIDSize int, offsetSize int, segmentSize int, lengthSize int) error {
+	if count < 1 {
+		return nil
+	}
+	_, err := decoder.readBytes(count * hashIDSize)
+	if err != nil {
+		return err
+	}
+	offsets, err := decoder.readBytes(count * offsetSize)
+	if err != nil {
+		return err
+	}
+	segment, err := decoder.readBytes(segmentSize)
+	if err != nil {
+		return err
+	}
+
+	for i := 0; i < count; i++ {
+		var offset int
+		if offsetSize == 2 {
+			offset = int(binary.BigEndian.Uint16(offsets[i*2:]))
+		} else {
+			offset = int(binary.BigEndian.Uint32(offsets[i*4:]))
+		}
+		if offset+lengthSize > len(segment) {
+			return errOSONTruncated
+		}
+		var length int
+		if lengthSize == 1 {
+			length = int(segment[offset])
+		} else {
+			length = int(binary.BigEndian.Uint16(segment[offset:]))
+		}
+		offset += lengthSize
+		if offset+length > len(segment) {
+			return errOSONTruncated
+		}
+		decoder.fieldNames = append(decoder.fieldNames, string(segment[offset:offset+length]))
+	}
+
+	return nil
+}
+
+// decodeNode decodes the node at the current position
+func (decoder *osonDecoder) decodeNode() (interface{}, error) {
+	nodeType, err := decoder.readUint8()
+	if err != nil {
+		return nil, err
+	}
+	if nodeType&0x80 != 0 {
+		return decoder.decodeContainer(nodeType)
+	}
+
+	switch nodeType {
+	case osonTypeNull:
+		return nil, nil
+	case osonTypeTrue:
+		return true, nil
+	case osonTypeFalse:
+		return false, nil
+
+	case osonTypeDate, osonTypeTimestamp7:
+		return decoder.decodeTime(7)
+	case osonTypeTimestamp:
+		return decoder.decodeTime(11)
+	case osonTypeTimestampTZ:
+		return decoder.decodeTime(13)
+
+	case osonTypeBinaryFloat:
+		data, err := decoder.readBytes(4)
+		if err != nil {
+			return nil, err
+		}
+		bits := binary.BigEndian.Uint32(data)
+		if bits&0x80000000 != 0 {
+			bits &^= 0x80000000
+		} else {
+			bits = ^bits
+		}
+		return float64(math.Float32frombits(bits)), nil
+	case osonTypeBinaryDouble:
+		data, err := decoder.readBytes(8)
+		if err != nil {
+			return nil, err
+		}
+		bits := binary.BigEndian.Uint64(data)
+		if bits&0x8000000000000000 != 0 {
+			bits &^= 0x8000000000000000
+		} else {
+			bits = ^bits
+		}
+		return math.Float64frombits(bits), nil
+
+	case osonTypeIntervalDS:
+		data, err := decoder.readBytes(11)
+		if err != nil {
+			return nil, err
+		}
+		days := int64(int32(binary.BigEndian.Uint32(data[0:4]) - 0x80000000))
+		nanoseconds := int64(int32(binary.BigEndian.Uint32(data[7:11]) - 0x80000000))
+		return time.Duration(days)*24*time.Hour + time.Duration(int(data[4])-60)*time.Hour +
+			time.Duration(int(data[5])-60)*time.Minute + time.Duration(int(data[6])-60)*time.Second +
+			time.Duration(nanoseconds), nil
+	case osonTypeIntervalYM:
+		return nil, errors.New("OSON year to month interval is not supported")
+
+	case osonTypeStringLength8, osonTypeStringLength16, osonTypeStringLength32:
+		data, err := decoder.readLengthBytes(nodeType)
+		if err != nil {
+			return nil, err
+		}
+		return string(data), nil
+
+	case osonTypeNumberLength8:
+		data, err := decoder.readLengthBytes(nodeType)
+		if err != nil {
+			return nil, err
+		}
+		return decoder.decodeNumber(data)
+
+	case osonTypeID, osonTypeBinaryLength16, osonTypeBinaryLength32:
+		data, err := decoder.readLengthBytes(nodeType)
+		if err != nil {
+			return nil, err
+		}
+		return append([]byte(nil), data...), nil
+	}
+
+	switch {
+	case nodeType&0xf0 == 0x20 || nodeType&0xf0 == 0x60:
+		// number with the length minus 1 in the node type
+		data, err := decoder.readBytes(int(nodeType&0x0f) + 1)
+		if err != nil {
+			return nil, err
+		}
+		return decoder.decodeNumber(data)
+
+	case nodeType&0xf0 == 0x40 || nodeType&0xf0 == 0x50:
+		// integer with the length in the node type
+		data, err := decoder.readBytes(int(nodeType & 0x0f))
+		if err != nil {
+			return nil, err
+		}
+		return decoder.decodeNumber(data)
+
+	case nodeType&0xe0 == 0:
+		// string with the length in the node type
+		data, err := decoder.readBytes(int(nodeType))
+		if err != nil {
+			return nil, err
+		}
+		return string(data), nil
+	}
+
+	return nil, fmt.Errorf("OSON node type %#x is not supported", nodeType)
+}
+
+// decodeContainer decodes an object or array node
+func (decoder *osonDecoder) decodeContainer(nodeType uint8) (interface{}, error) {
+	isObject := nodeType&0x40 == 0
+	containerOffset := decoder.pos - decoder.treeSegmentPos - 1
+
+	count, isShared, err := decoder.readChildCount(nodeType)
+	if err != nil {
+		return nil, err
+	}
+
+	var fieldIDsPos, offsetsPos int
+	switch {
+	case isShared:
+		// the field ids are shared with the object at the offset
+		var offset int
+		offset, err = decoder.readOffset(nodeType)
+		if err != nil {
+			return nil, err
+		}
+		offsetsPos = decoder.pos
+		decoder.pos = decoder.treeSegmentPos + offset
+		var sharedType uint8
+		sharedType, err = decoder.readUint8()
+		if err != nil {
+			return nil, err
+		}
+		count, _, err = decoder.readChildCount(sharedType)
+		if err != nil {
+			return nil, err
+		}
+		fieldIDsPos = decoder.pos
+	case isObject:
+		fieldIDsPos = decoder.pos
+		offsetsPos = decoder.pos + decoder.fieldIDLength*count
+	default:
+		offsetsPos = decoder.pos
+	}
+
+	var object map[string]interface{}
+	var array []interface{}
+	if isObject {
+		object = make(map[string]interface{}, count)
+	} else {
+		array = make([]interface{}, count)
+	}
+
+	for i := 0; i < count; i++ {
+		var name string
+		if isObject {
+			decoder.pos = fieldIDsPos
+			var fieldID uint32
+			switch decoder.fieldIDLength {
+			case 1:
+				var id uint8
+				id, err = decoder.readUint8()
+				fieldID = uint32(id)
+			case 2:
+				var id uint16
+				id, err = decoder.readUint16()
+				fieldID = uint32(id)
+			default:
+				fieldID, err = decoder.readUint32()
+			}
+			if err != nil {
+				return nil, err
+			}
+			if fieldID < 1 || int(fieldID) > len(decoder.fieldNames) {
+				return nil, fmt.Errorf("OSON field id %v is not valid", fieldID)
+			}
+			name = decoder.fieldNames[fieldID-1]
+			fieldIDsPos = decoder.pos
+		}
+
+		decoder.pos = offsetsPos
+		offset, err := decoder.readOffset(nodeType)
+		if err != nil {
+			return nil, err
+		}
+		if decoder.relativeOffsets {
+			offset += containerOffset
+		}
+		offsetsPos = decoder.pos
+
+		decoder.pos = decoder.treeSegmentPos + offset
+		value, err := decoder.decodeNode()
+		if err != nil {
+			return nil, err
+		}
+		if isObject {
+			object[name] = value
+		} else {
+			array[i] = value
+		}
+	}
+
+	if isObject {
+		return object, nil
+	}
+	return array, nil
+}
+
+// readChildCount reads the number of children of a container node.
+// A shared container has the field ids of another object node and the count is not read.
+func (decoder *osonDecoder) readChildCount(nodeType uint8) (int, bool, error) {
+	switch nodeType & osonContainerSharedField {
+	case 0:
+		count, err := decoder.readUint8()
+		return int(count), false, err
+	case osonContainerChildren16:
+		count, err := decoder.readUint16()
+		return int(count), false, err
+	case osonContainerChildren32:
+		count, err := decoder.readUint32()
+		return int(count), false, err
+	}
+	return 0, true, nil
+}
+
+// readOffset reads a child offset of a container node
+func (decoder *osonDecoder) readOffset(nodeType uint8) (int, error) {
+	if nodeType&osonContainerOffsets32 != 0 {
+		offset, err := decoder.readUint32()
+		return int(offset), err
+	}
+	offset, err := decoder.readUint16()
+	return int(offset), err
+}
+
+// readLengthBytes reads the length of a string, number, or binary node, then the data
+func (decoder *osonDecoder) readLengthBytes(nodeType uint8) ([]byte, error) {
+	var length int
+	switch nodeType {
+	case osonTypeStringLength8, osonTypeNumberLength8, osonTypeID:
+		value, err := decoder.readUint8()
+		if err != nil {
+			return nil, err
+		}
+		length = int(value)
+	case osonTypeStringLength16, osonTypeBinaryLength16:
+		value, err := decoder.readUint16()
+		if err != nil {
+			return nil, err
+		}
+		length = int(value)
+	default:
+		value, err := decoder.readUint32()
+		if err != nil {
+			return nil, err
+		}
+		length = int(value)
+	}
+	return decoder.readBytes(length)
+}
+
+// decodeNumber decodes the Oracle NUMBER format as an exact json.Number
+func (decoder *osonDecoder) decodeNumber(data []byte) (interface{}, error) {
+	number, err := decodeNumber(data)
+	if err != nil {
+		return nil, err
+	}
+	return json.Number(number), nil
+}
+
+// decodeTime decodes the Oracle DATE, TIMESTAMP, and TIMESTAMP WITH TIME ZONE formats.
+// TIMESTAMP WITH TIME ZONE is stored as UTC followed by the time zone hour and minute.
+func (decoder *osonDecoder) decodeTime(length int) (interface{}, error) {
+	data, err := decoder.readBytes(length)
+	if err != nil {
+		return nil, err
+	}
+
+	nanoseconds := 0
+	if length >= 11 {
+		nanoseconds = int(binary.BigEndian.Uint32(data[7:11]))
+	}
+	location := decoder.location
+	if length >= 13 {
+		location = time.UTC
+	}
+	aTime := time.Date((int(data[0])-100)*100+int(data[1])-100, time.Month(data[2]), int(data[3]),
+		int(data[4])-1, int(data[5])-1, int(data[6])-1, nanoseconds, location)
+	if length >= 13 && data[11]&0x80 == 0 {
+		offset := (int(data[11])-20)*3600 + (int(data[12])-60)*60
+		aTime = aTime.In(time.FixedZone("", offset))
+	}
+
+	return aTime, nil
+}
+
+func (decoder *osonDecoder) readBytes(length int) ([]byte, error) {
+	if length < 0 || decoder.pos+length > len(decoder.data) {
+		return nil, errOSONTruncated
+	}
+	data := decoder.data[decoder.pos : decoder.pos+length]
+	decoder.pos += length
+	return data, nil
+}
+
+func (decoder *osonDecoder) readUint8() (uint8, error) {
+	data, err := decoder.readBytes(1)
+	if err != nil {
+		return 0, err
+	}
+	return data[0], nil
+}
+
+func (decoder *osonDecoder) readUint16() (uint16, error) {
+	data, err := decoder.readBytes(2)
+	if err != nil {
+		return 0, err
+	}
+	return binary.BigEndian.Uint16(data), nil
+}
+
+func (decoder *osonDecoder) readUint32() (uint32, error) {
+	data, err := decoder.readBytes(4)
+	if err != nil {
+		return 0, err
+	}
+	return binary.BigEndian.Uint32(data), nil
+}
+
+// osonFieldName is a field name of an OSON image being encoded
+type osonFieldName struct {
+	name   string
+	hashID uint32
+	id     int
+	offset int
+}
+
+// osonEncoder encodes an OSON image
+type osonEncoder struct {
+	location      *time.Location
+	fieldNames    map[string]*osonFieldName
+	fieldIDLength int
+	tree          bytes.Buffer
+}
+
+// encodeOSON encodes a Go value as an OSON image.
+// Maps with string keys are objects, slices other than []byte are arrays,
+// integers and exact numbers like json.Number are Oracle NUMBER, float64 and float32 are binary doubles and floats,
+// time.Time is a timestamp in location, and time.Duration is a day to second interval.
+// A json.RawMessage is parsed, other values like structs are marshaled with encoding/json first.
+func encodeOSON(value interface{}, location *time.Location) ([]byte, error) {
+	value, err := osonValue(value)
+	if err != nil {
+		return nil, err
+	}
+
+	encoder := &osonEncoder{
+		location:   location,
+		fieldNames: make(map[string]*osonFieldName),
+	}
+	return encoder.encode(value)
+}
+
+// osonValue converts a Go value to the values encoded by osonEncoder:
+// nil, bool, string, json.Number, float64, float32, time.Time, time.Duration, []byte,
+// map[string]interface{}, and []interface{}
+func osonValue(value interface{}) (interface{}, error) {
+	switch data := value.(type) {
+	case nil, bool, string, json.Number, float64, float32, time.Time, time.Duration, []byte:
+		return value, nil
+	case *big.Int, *big.Rat:
+		number, err := numberToDecimalString(value)
+		if err != nil {
+			return nil, err
+		}
+		if number == "" {
+			return nil, nil
+		}
+		return json.Number(number), nil
+	case json.RawMessage:
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.UseNumber()
+		var parsed interface{}
+		err := decoder.Decode(&parsed)
+		if err != nil {
+			return nil, err
+		}
+		return osonValue(parsed)
+	case JSON:
+		return osonValue(data.Value)
+	}
+
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if rv.IsNil() {
+			return nil, nil
+		}
+		if _, ok := value.(json.Marshaler); !ok {
+			return osonValue(rv.Elem().Interface())
+		}
+	case reflect.Bool:
+		return rv.Bool(), nil
+	case reflect.String:
+		return rv.String(), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return json.Number(strconv.FormatInt(rv.Int(), 10)), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return json.Number(strconv.FormatUint(rv.Uint(), 10)), nil
+	case reflect.Float32:
+		return float32(rv.Float()), nil
+	case reflect.Float64:
+		return rv.Float(), nil
+	case reflect.Map:
+		if rv.Type().Key().Kind() != reflect.String {
+			break
+		}
+		if rv.IsNil() {
+			return nil, nil
+		}
+		object := make(map[string]interface{}, rv.Len())
+		for _, key := range rv.MapKeys() {
+			element, err := osonValue(rv.MapIndex(key).Interface())
+			if err != nil {
+				return nil, fmt.Errorf("field %v - error: %v", key.String(), err)
+			}
+			object[key.String()] = element
+		}
+		return object, nil
+	case reflect.Slice, reflect.Array:
+		if rv.Kind() == reflect.Slice && rv.IsNil() {
+			return nil, nil
+		}
+		if rv.Type().Elem().Kind() == reflect.Uint8 && rv.Kind() == reflect.Slice {
+			return rv.Bytes(), nil
+		}
+		array := make([]interface{}, rv.Len())
+		for i := range array {
+			element, err := osonValue(rv.Index(i).Interface())
+			if err != nil {
+				return nil, fmt.Errorf("element %v - error: %v", i, err)
+			}
+			array[i] = element
+		}
+		return array, nil
+	}
+
+	// structs and other values are converted like encoding/json
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	return osonValue(json.RawMessage(data))
+}
+
+// encode encodes the tree segment, then returns the header, field names segment, and tree segment
+func (encoder *osonEncoder) encode(value interface{}) ([]byte, error) {
+	_, isObject := value.(map[string]interface{})
+	_, isArray := value.([]interface{})
+	isScalar := !isObject && !isArray
+
+	var namesSegment []byte
+	var names []*osonFieldName
+	if !isScalar {
+		err := encoder.addFieldNames(value)
+		if err != nil {
+			return nil, err
+		}
+		names, namesSegment = encoder.fieldNamesSegment()
+	}
+
+	err := encoder.encodeNode(value)
+	if err != nil {
+		return nil, err
+	}
+	tree := encoder.tree.Bytes()
+
+	flags := uint16(osonFlagInlineLeaf)
+	if isScalar {
+		flags |= osonFlagIsScalar
+	} else {
+		flags |= osonFlagHashIDUint8 | osonFlagTinyNodesStat
+		switch encoder.fieldIDLength {
+		case 2:
+			flags |= osonFlagNumFieldNamesUint16
+		case 4:
+			flags |= osonFlagNumFieldNamesUint32
+		}
+		if len(namesSegment) > math.MaxUint16 {
+			flags |= osonFlagFieldNamesSegUint32
+		}
+	}
+	if len(tree) > math.MaxUint16 {
+		flags |= osonFlagTreeSegUint32
+	}
+
+	var buffer bytes.Buffer
+	buffer.Write([]byte{osonMagic1, osonMagic2, osonMagic3, osonVersionMaxFieldName255})
+	writeUint16(&buffer, flags)
+
+	if !isScalar {
+		switch encoder.fieldIDLength {
+		case 1:
+			buffer.WriteByte(byte(len(names)))
+		case 2:
+			writeUint16(&buffer, uint16(len(names)))
+		default:
+			writeUint32(&buffer, uint32(len(names)))
+		}
+		if flags&osonFlagFieldNamesSegUint32 != 0 {
+			writeUint32(&buffer, uint32(len(namesSegment)))
+		} else {
+			writeUint16(&buffer, uint16(len(namesSegment)))
+		}
+	}
+
+	if flags&osonFlagTreeSegUint32 != 0 {
+		writeUint32(&buffer, uint32(len(tree)))
+	} else {
+		writeUint16(&buffer, uint16(len(tree)))
+	}
+
+	if !isScalar {
+		// number of tiny nodes
+		writeUint16(&buffer, 0)
+		for _, name := range names {
+			buffer.WriteByte(byte(name.hashID))
+		}
+		for _, name := range names {
+			if flags&osonFlagFieldNamesSegUint32 != 0 {
+				writeUint32(&buffer, uint32(name.offset))
+			} else {
+				writeUint16(&buffer, uint16(name.offset))
+			}
+		}
+		buffer.Write(namesSegment)
+	}
+
+	buffer.Write(tree)
+	return buffer.Bytes(), nil
+}
+
+// addFieldNames adds the field names of all the objects in the value
+func (encoder *osonEncoder) addFieldNames(value interface{}) error {
+	switch data := value.(type) {
+	case map[string]interface{}:
+		for name, element := range data {
+			if _, ok := encoder.fieldNames[name]; !ok {
+				if len(name) > 255 {
+					return fmt.Errorf("field name %.20q... is longer than 255 bytes", name)
+				}
+				encoder.fieldNames[name] = &osonFieldName{name: name, hashID: osonHashID(name)}
+			}
+			err := encoder.addFieldNames(element)
+			if err != nil {
+				return err
+			}
+		}
+	case []interface{}:
+		for _, element := range data {
+			err := encoder.addFieldNames(element)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// fieldNamesSegment sorts the field names by hash id, length, then name, sets the ids and offsets,
+// then returns the sorted names and the field names segment
+func (encoder *osonEncoder) fieldNamesSegment() ([]*osonFieldName, []byte) {
+	names := make([]*osonFieldName, 0, len(encoder.fieldNames))
+	for _, name := range encoder.fieldNames {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if byte(names[i].hashID) != byte(names[j].hashID) {
+			return byte(names[i].hashID) < byte(names[j].hashID)
+		}
+		if len(names[i].name) != len(names[j].name) {
+			return len(names[i].name) < len(names[j].name)
+		}
+		return names[i].name < names[j].name
+	})
+
+	switch {
+	case len(names) > math.MaxUint16:
+		encoder.fieldIDLength = 4
+	case len(names) > math.MaxUint8:
+		encoder.fieldIDLength = 2
+	default:
+		encoder.fieldIDLength = 1
+	}
+
+	var segment bytes.Buffer
+	for i, name := range names {
+		name.id = i + 1
+		name.offset = segment.Len()
+		segment.WriteByte(byte(len(name.name)))
+		segment.WriteString(name.name)
+	}
+
+	return names, segment.Bytes()
+}
+
+// osonHashID returns the 32 bit FNV-1a hash of a field name
+func osonHashID(name string) uint32 {
+	hashID := uint32(0x811c9dc5)
+	for i := 0; i < len(name); i++ {
+		hashID ^= uint32(name[i])
+		hashID *= 16777619
+	}
+	return hashID
+}
+
+// encodeNode encodes a value from osonValue into the tree segment
+func (encoder *osonEncoder) encodeNode(value interface{}) error {
+	tree := &encoder.tree
+
+	switch data := value.(type) {
+	case nil:
+		tree.WriteByte(osonTypeNull)
+
+	case bool:
+		if data {
+			tree.WriteByte(osonTypeTrue)
+		} else {
+			tree.WriteByte(osonTypeFalse)
+		}
+
+	case string:
+		switch {
+		case len(data) <= math.MaxUint8:
+			tree.WriteByte(osonTypeStringLength8)
+			tree.WriteByte(byte(len(data)))
+		case len(data) <= math.MaxUint16:
+			tree.WriteByte(osonTypeStringLength16)
+			writeUint16(tree, uint16(len(data)))
+		default:
+			tree.WriteByte(osonTypeStringLength32)
+			writeUint32(tree, uint32(len(data)))
+		}
+		tree.WriteString(data)
+
+	case json.Number:
+		number, err := encodeNumber(string(data))
+		if err != nil {
+			return err
+		}
+		tree.WriteByte(osonTypeNumberLength8)
+		tree.WriteByte(byte(len(number)))
+		tree.Write(number)
+
+	case float64:
+		bits := math.Float64bits(data)
+		if bits&0x8000000000000000 == 0 {
+			bits |= 0x8000000000000000
+		} else {
+			bits = ^bits
+		}
+		tree.WriteByte(osonTypeBinaryDouble)
+		var buffer [8]byte
+		binary.BigEndian.PutUint64(buffer[:], bits)
+		tree.Write(buffer[:])
+
+	case float32:
+		bits := math.Float32bits(data)
+		if bits&0x80000000 == 0 {
+			bits |= 0x80000000
+		} else {
+			bits = ^bits
+		}
+		tree.WriteByte(osonTypeBinaryFloat)
+		writeUint32(tree, bits)
+
+	case time.Time:
+		data = data.In(encoder.location)
+		tree.WriteByte(osonTypeTimestamp)
+		tree.Write([]byte{byte(data.Year()/100 + 100), byte(data.Year()%100 + 100), byte(data.Month()), byte(data.Day()),
+			byte(data.Hour() + 1), byte(data.Minute() + 1), byte(data.Second() + 1)})
+		writeUint32(tree, uint32(data.Nanosecond()))
+
+	case time.Duration:
+		days := data / (24 * time.Hour)
+		data -= days * 24 * time.Hour
+		hours := data / time.Hour
+		data -= hours * time.Hour
+		minutes := data / time.Minute
+		data -= minutes * time.Minute
+		seconds := data / time.Second
+		data -= seconds * time.Second
+		tree.WriteByte(osonTypeIntervalDS)
+		writeUint32(tree, uint32(int32(days))+0x80000000)
+		tree.Write([]byte{byte(hours + 60), byte(minutes + 60), byte(seconds + 60)})
+		writeUint32(tree, uint32(int32(data))+0x80000000)
+
+	case []byte:
+		if len(data) <= math.MaxUint16 {
+			tree.WriteByte(osonTypeBinaryLength16)
+			writeUint16(tree, uint16(len(data)))
+		} else {
+			tree.WriteByte(osonTypeBinaryLength32)
+			writeUint32(tree, uint32(len(data)))
+		}
+		tree.Write(data)
+
+	case []interface{}:
+		encoder.encodeContainerHeader(osonTypeArray, len(data))
+		offsetsPos := tree.Len()
+		tree.Write(make([]byte, 4*len(data)))
+		for _, element := range data {
+			binary.BigEndian.PutUint32(tree.Bytes()[offsetsPos:], uint32(tree.Len()))
+			offsetsPos += 4
+			err := encoder.encodeNode(element)
+			if err != nil {
+				return err
+			}
+		}
+
+	case map[string]interface{}:
+		// children are in field id order
+		names := make([]*osonFieldName, 0, len(data))
+		for name := range data {
+			names = append(names, encoder.fieldNames[name])
+		}
+		sort.Slice(names, func(i, j int) bool { return names[i].id < names[j].id })
+
+		encoder.encodeContainerHeader(osonTypeObject, len(data))
+		fieldIDsPos := tree.Len()
+		offsetsPos := fieldIDsPos + encoder.fieldIDLength*len(data)
+		tree.Write(make([]byte, (encoder.fieldIDLength+4)*len(data)))
+		for _, name := range names {
+			switch encoder.fieldIDLength {
+			case 1:
+				tree.Bytes()[fieldIDsPos] = byte(name.id)
+			case 2:
+				binary.BigEndian.PutUint16(tree.Bytes()[fieldIDsPos:], uint16(name.id))
+			default:
+				binary.BigEndian.PutUint32(tree.Bytes()[fieldIDsPos:], uint32(name.id))
+			}
+			fieldIDsPos += encoder.fieldIDLength
+			binary.BigEndian.PutUint32(tree.Bytes()[offsetsPos:], uint32(tree.Len()))
+			offsetsPos += 4
+			err := encoder.encodeNode(data[name.name])
+			if err != nil {
+				return fmt.Errorf("field %v - error: %v", name.name, err)
+			}
+		}
+
+	default:
+		return fmt.Errorf("can not encode %T as OSON", value)
+	}
+
+	return nil
+}
+
+// encodeContainerHeader writes the node type and number of children of a container with 32 bit offsets
+func (encoder *osonEncoder) encodeContainerHeader(nodeType uint8, count int) {
+	tree := &encoder.tree
+	nodeType |= osonContainerOffsets32
+	switch {
+	case count > math.MaxUint16:
+		tree.WriteByte(nodeType | osonContainerChildren32)
+		writeUint32(tree, uint32(count))
+	case count > math.MaxUint8:
+		tree.WriteByte(nodeType | osonContainerChildren16)
+		writeUint16(tree, uint16(count))
+	default:
+		tree.WriteByte(nodeType)
+		tree.WriteByte(byte(count))
+	}
+}
+
+func writeUint16(buffer *bytes.Buffer, value uint16) {
+	var data [2]byte
+	binary.BigEndian.PutUint16(data[:], value)
+	buffer.Write(data[:])
+}
+
+func writeUint32(buffer *bytes.Buffer, value uint32) {
+	var data [4]byte
+	binary.BigEndian.PutUint32(data[:], value)
+	buffer.Write(data[:])
+}
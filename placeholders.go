@@ -0,0 +1,168 @@
+package oci8
+
+import (
+	"strconv"
+	"strings"
+)
+
+// placeholderStyle is a bit mask of placeholder styles that are rewritten to Oracle placeholders
+type placeholderStyle uint8
+
+const (
+	// placeholderQuestion rewrites ? to :1, :2, and so on
+	placeholderQuestion placeholderStyle = 1 << iota
+	// placeholderDollar rewrites $1 to :1
+	placeholderDollar
+	// placeholderAt rewrites @name to :name
+	placeholderAt
+)
+
+// placeholders rewrites the placeholder styles in query to Oracle placeholders.
+// Only placeholders in code positions are rewritten. Placeholders inside of string literals,
+// q-quoted strings, quoted identifiers, and comments are left as is.
+//
+// A $N placeholder can be out of order or repeated, so when any are rewritten the number of
+// arguments is returned and the placeholders must be bound by name with the argument ordinal.
+// A ? placeholder is then the argument of its ordinal too. Otherwise 0 is returned.
+func placeholders(query string, styles placeholderStyle) (string, int) {
+	if styles == 0 {
+		return query, 0
+	}
+
+	var buffer strings.Builder
+	last := 0 // query up to last has been written to buffer
+	n := 0
+	dollar := false
+	ordinals := 0
+	i := 0
+	for i < len(query) {
+		c := query[i]
+		switch {
+
+		case c == '\'':
+			// string literal, an escaped quote is handled as two string literals next to each other
+			i = skipPast(query, i+1, "'")
+
+		case c == '"':
+			// quoted identifier
+			i = skipPast(query, i+1, `"`)
+
+		case c == '-' && strings.HasPrefix(query[i:], "--"):
+			// single line comment
+			i = skipPast(query, i+2, "\n")
+
+		case c == '/' && strings.HasPrefix(query[i:], "/*"):
+			// multi line comment
+			i = skipPast(query, i+2, "*/")
+
+		case c == '?' && styles&placeholderQuestion != 0:
+			n++
+			buffer.WriteString(query[last:i])
+			buffer.WriteString(":" + strconv.Itoa(n))
+			i++
+			last = i
+
+		case c == '$' && styles&placeholderDollar != 0 && i+1 < len(query) && isDigitByte(query[i+1]):
+			// identifiers are skipped whole below, so this $ is not part of an identifier like v$session.
+			// The digits are written as is.
+			buffer.WriteString(query[last:i])
+			buffer.WriteByte(':')
+			i++
+			last = i
+			start := i
+			for i < len(query) && isDigitByte(query[i]) {
+				i++
+			}
+			ordinal, err := strconv.Atoi(query[start:i])
+			if err == nil && ordinal > ordinals {
+				ordinals = ordinal
+			}
+			dollar = true
+
+		case c == '@' && styles&placeholderAt != 0 && i+1 < len(query) && isLetterByte(query[i+1]) &&
+			(i == 0 || (!isIdentifierByte(query[i-1]) && query[i-1] != '"')):
+			// @ right after an identifier is a database link, like table@dblink.
+			// The name is written as is.
+			buffer.WriteString(query[last:i])
+			buffer.WriteByte(':')
+			i++
+			last = i
+
+		case isIdentifierByte(c):
+			// skip identifiers, keywords, and numbers whole
+			start := i
+			for i < len(query) && isIdentifierByte(query[i]) {
+				i++
+			}
+			if i+1 < len(query) && query[i] == '\'' && isQQuotePrefix(query[start:i]) {
+				// q-quoted string, like q'[it's]' or nq'{...}'
+				i = skipPast(query, i+2, string([]byte{qQuoteClosing(query[i+1]), '\''}))
+			}
+
+		default:
+			i++
+		}
+	}
+
+	buffer.WriteString(query[last:])
+	if !dollar {
+		return buffer.String(), 0
+	}
+	if n > ordinals {
+		ordinals = n
+	}
+	return buffer.String(), ordinals
+}
+
+// skipPast returns the index after the first end found in query starting at start,
+// or the length of query if end is not found
+func skipPast(query string, start int, end string) int {
+	if start >= len(query) {
+		return len(query)
+	}
+	index := strings.Index(query[start:], end)
+	if index < 0 {
+		return len(query)
+	}
+	return start + index + len(end)
+}
+
+// isQQuotePrefix returns true if word followed by a quote starts a q-quoted string
+func isQQuotePrefix(word string) bool {
+	switch word {
+	case "q", "Q", "nq", "nQ", "Nq", "NQ":
+		return true
+	}
+	return false
+}
+
+// qQuoteClosing returns the closing delimiter for a q-quoted string opening delimiter
+func qQuoteClosing(opening byte) byte {
+	switch opening {
+	case '[':
+		return ']'
+	case '{':
+		return '}'
+	case '(':
+		return ')'
+	case '<':
+		return '>'
+	}
+	return opening
+}
+
+// isIdentifierByte returns true if c can be part of an unquoted identifier, keyword, or number.
+// Bytes of multibyte UTF-8 characters are included.
+func isIdentifierByte(c byte) bool {
+	return isLetterByte(c) || isDigitByte(c) || c == '_' || c == '$' || c == '#' || c >= 0x80
+}
+
+// isLetterByte returns true if c is an ASCII letter
+func isLetterByte(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+// isDigitByte returns true if c is an ASCII digit
+func isDigitByte(c byte) bool {
+	return c >= '0' && c <= '9'
+}
@@ -0,0 +1,228 @@
+package oci8
+
+// #include "oci8.go.h"
+import "C"
+
+import (
+	"database/sql"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"reflect"
+	"time"
+	"unsafe"
+)
+
+// plsqlArrayOutMaxSize is the max size of string and []byte elements of an OUT PL/SQL associative array,
+// unless an IN element is longer
+const plsqlArrayOutMaxSize = 4000
+
+// makePLSQLArrayBind lays out the elements of a Go slice as a PL/SQL associative array bind,
+// with an indicator and length for each element.
+// For an out bind the capacity of the slice is the max number of elements, otherwise the length is.
+func (stmt *Stmt) makePLSQLArrayBind(sbind *bindStruct, rv reflect.Value, isOut bool, isIn bool) error {
+	elemType := rv.Type().Elem()
+	dataType, maxSize, descriptorType, err := elementBindType(elemType, plsqlArrayOutMaxSize)
+	if err != nil {
+		return err
+	}
+
+	count := 0
+	if isIn {
+		count = rv.Len()
+	}
+	maxLength := rv.Len()
+	if isOut {
+		maxLength = rv.Cap()
+		if maxLength < 1 {
+			return fmt.Errorf("slice capacity is the max number of elements and must be greater than 0")
+		}
+	}
+	if maxLength < 1 {
+		// a max array length of 0 would be a scalar bind
+		maxLength = 1
+	}
+
+	if dataType == C.SQLT_CHR || dataType == C.SQLT_BIN {
+		if !isOut {
+			maxSize = 1
+		}
+		for i := 0; i < count; i++ {
+			size := elementSize(rv.Index(i))
+			if size > 32767 {
+				return fmt.Errorf("element %v length %v is greater than max PL/SQL array element length 32767", i, size)
+			}
+			if size > maxSize {
+				maxSize = size
+			}
+		}
+	}
+
+	// replace the scalar length and indicator with arrays
+	C.free(unsafe.Pointer(sbind.length))
+	C.free(unsafe.Pointer(sbind.indicator))
+	sbind.dataType = dataType
+	sbind.maxSize = C.sb4(maxSize)
+	sbind.arrayLength = maxLength
+	sbind.plsqlArrayMax = C.ub4(maxLength)
+	sbind.plsqlArrayCurrent = (*C.ub4)(C.malloc(C.sizeof_ub4))
+	*sbind.plsqlArrayCurrent = C.ub4(count)
+	sbind.length = (*C.ub2)(C.malloc(C.size_t(maxLength) * C.sizeof_ub2))
+	sbind.indicator = (*C.sb2)(C.malloc(C.size_t(maxLength) * C.sizeof_sb2))
+	sbind.pbuf = C.calloc(C.size_t(maxLength), C.size_t(maxSize))
+	lengths := (*[1 << 30]C.ub2)(unsafe.Pointer(sbind.length))[:maxLength:maxLength]
+	indicators := (*[1 << 30]C.sb2)(unsafe.Pointer(sbind.indicator))[:maxLength:maxLength]
+	buffer := (*[1 << 30]byte)(sbind.pbuf)[: maxLength*maxSize : maxLength*maxSize]
+
+	for i := 0; i < maxLength; i++ {
+		element := buffer[i*maxSize : (i+1)*maxSize]
+		indicators[i] = -1 // set to null
+		lengths[i] = C.ub2(maxSize)
+
+		if i >= count {
+			if descriptorType != 0 {
+				// every element needs a descriptor for the out value
+				var descriptorP *unsafe.Pointer
+				descriptorP, _, err = stmt.conn.ociDescriptorAlloc(descriptorType, 0)
+				if err != nil {
+					return fmt.Errorf("allocate descriptor for element %v - error: %v", i, err)
+				}
+				*(*unsafe.Pointer)(unsafe.Pointer(&element[0])) = *descriptorP
+			}
+			continue
+		}
+
+		var length int
+		var isNull bool
+		length, isNull, err = stmt.setElementBuffer(element, rv.Index(i), dataType)
+		if err != nil {
+			return fmt.Errorf("element %v - error: %v", i, err)
+		}
+		if !isNull {
+			indicators[i] = 0
+			lengths[i] = C.ub2(length)
+		}
+	}
+
+	return nil
+}
+
+// elementSize returns the length of a string or []byte slice element
+func elementSize(value reflect.Value) int {
+	if value.Type() == typeNullString {
+		return len(value.Interface().(sql.NullString).String)
+	}
+	return value.Len()
+}
+
+// setElementBuffer sets the element buffer from a slice element value of the bind data type,
+// then returns the length of the value and if the value is null.
+// A time element buffer is set to a new descriptor, even if the value is null.
+func (stmt *Stmt) setElementBuffer(element []byte, value reflect.Value, dataType C.ub2) (int, bool, error) {
+	switch dataType {
+
+	case C.SQLT_CHR:
+		if value.Type() == typeNullString {
+			nullString := value.Interface().(sql.NullString)
+			return copy(element, nullString.String), !nullString.Valid, nil
+		}
+		return copy(element, value.String()), false, nil
+
+	case C.SQLT_BIN:
+		return copy(element, value.Bytes()), value.IsNil(), nil
+
+	case C.SQLT_INT:
+		var data uint64
+		isNull := false
+		switch {
+		case value.Type() == typeNullInt64:
+			nullInt64 := value.Interface().(sql.NullInt64)
+			data, isNull = uint64(nullInt64.Int64), !nullInt64.Valid
+		case value.Type() == typeNullBool:
+			nullBool := value.Interface().(sql.NullBool)
+			if nullBool.Bool {
+				data = 1
+			}
+			isNull = !nullBool.Valid
+		case value.Kind() == reflect.Bool:
+			if value.Bool() {
+				data = 1
+			}
+		case value.Kind() >= reflect.Uint && value.Kind() <= reflect.Uintptr:
+			data = value.Uint()
+		default:
+			data = uint64(value.Int())
+		}
+		binary.LittleEndian.PutUint64(element, data)
+		return 8, isNull, nil
+
+	case C.SQLT_BDOUBLE:
+		var data float64
+		isNull := false
+		if value.Type() == typeNullFloat64 {
+			nullFloat64 := value.Interface().(sql.NullFloat64)
+			data, isNull = nullFloat64.Float64, !nullFloat64.Valid
+		} else {
+			data = value.Float()
+		}
+		binary.LittleEndian.PutUint64(element, math.Float64bits(data))
+		return 8, isNull, nil
+
+	case C.SQLT_TIMESTAMP_TZ:
+		aTime := value.Interface().(time.Time)
+		dateTimePP, err := stmt.conn.timeToOCIDateTime(&aTime)
+		if err != nil {
+			return 0, false, fmt.Errorf("timeToOCIDateTime error: %v", err)
+		}
+		*(*unsafe.Pointer)(unsafe.Pointer(&element[0])) = *dateTimePP
+		return int(sizeOfNilPointer), false, nil
+
+	case C.SQLT_NUM:
+		number, err := numberToDecimalString(value.Interface())
+		if err != nil {
+			return 0, false, err
+		}
+		if number == "" {
+			return 0, true, nil
+		}
+		buffer, err := encodeNumber(number)
+		if err != nil {
+			return 0, false, err
+		}
+		return copy(element, buffer), false, nil
+
+	}
+
+	return 0, false, fmt.Errorf("unhandled element data type %v", dataType)
+}
+
+// outputPLSQLArray sets the slice of the out bind destination to the elements of the PL/SQL associative array
+func (stmt *Stmt) outputPLSQLArray(bind *bindStruct) error {
+	count := int(*bind.plsqlArrayCurrent)
+	if count > bind.arrayLength {
+		return fmt.Errorf("element count %v is greater than max %v", count, bind.arrayLength)
+	}
+
+	sliceValue := reflect.ValueOf(bind.out.Dest).Elem()
+	elemType := sliceValue.Type().Elem()
+	// keep the capacity so the destination can be used again as an out bind
+	values := reflect.MakeSlice(sliceValue.Type(), count, bind.arrayLength)
+
+	maxSize := int(bind.maxSize)
+	lengths := (*[1 << 30]C.ub2)(unsafe.Pointer(bind.length))[:count:count]
+	indicators := (*[1 << 30]C.sb2)(unsafe.Pointer(bind.indicator))[:count:count]
+
+	for i := 0; i < count; i++ {
+		if indicators[i] == -1 {
+			// null, leave as zero value
+			continue
+		}
+		err := stmt.setElementValue(values.Index(i), elemType, bind.dataType, unsafe.Pointer(uintptr(bind.pbuf)+uintptr(i*maxSize)), int(lengths[i]))
+		if err != nil {
+			return fmt.Errorf("element %v - error: %v", i, err)
+		}
+	}
+
+	sliceValue.Set(values)
+	return nil
+}
@@ -0,0 +1,148 @@
+#include "oci8.go.h"
+#include <string.h>
+
+// oci8ReturningBindNew allocates a returning bind for values of dataType.
+// descriptorType is the descriptor type for each value if the data type uses descriptors, otherwise 0.
+oci8ReturningBind *oci8ReturningBindNew(OCIEnv *env, OCIError *errHandle, ub2 dataType, ub4 maxSize, ub4 descriptorType) {
+	oci8ReturningBind *returningBind = calloc(1, sizeof(oci8ReturningBind));
+	if (returningBind == NULL) {
+		return NULL;
+	}
+	returningBind->env = env;
+	returningBind->errHandle = errHandle;
+	returningBind->dataType = dataType;
+	returningBind->maxSize = maxSize;
+	returningBind->descriptorType = descriptorType;
+	return returningBind;
+}
+
+// oci8ReturningBindGrow grows the returning bind arrays to hold at least rows
+static int oci8ReturningBindGrow(oci8ReturningBind *returningBind, ub4 rows) {
+	ub4 capacity = returningBind->capacity * 2;
+	if (capacity < 8) {
+		capacity = 8;
+	}
+	if (capacity < rows) {
+		capacity = rows;
+	}
+
+	void **buffers = realloc(returningBind->buffers, capacity * sizeof(void *));
+	if (buffers == NULL) {
+		return 0;
+	}
+	returningBind->buffers = buffers;
+	memset(buffers + returningBind->capacity, 0, (capacity - returningBind->capacity) * sizeof(void *));
+
+	ub4 *lengths = realloc(returningBind->lengths, capacity * sizeof(ub4));
+	if (lengths == NULL) {
+		return 0;
+	}
+	returningBind->lengths = lengths;
+
+	sb2 *indicators = realloc(returningBind->indicators, capacity * sizeof(sb2));
+	if (indicators == NULL) {
+		return 0;
+	}
+	returningBind->indicators = indicators;
+
+	ub2 *rcodes = realloc(returningBind->rcodes, capacity * sizeof(ub2));
+	if (rcodes == NULL) {
+		return 0;
+	}
+	returningBind->rcodes = rcodes;
+
+	returningBind->capacity = capacity;
+	return 1;
+}
+
+// oci8ReturningInBind is the OCIBindDynamic in bind callback, the in value is always null
+static sb4 oci8ReturningInBind(void *ictxp, OCIBind *bindp, ub4 iter, ub4 index, void **bufpp, ub4 *alenp, ub1 *piecep, void **indp) {
+	oci8ReturningBind *returningBind = ictxp;
+	returningBind->nullIndicator = -1;
+	*bufpp = NULL;
+	*alenp = 0;
+	*piecep = OCI_ONE_PIECE;
+	*indp = &returningBind->nullIndicator;
+	return OCI_CONTINUE;
+}
+
+// oci8ReturningOutBind is the OCIBindDynamic out bind callback, called for each row returned by each iteration
+static sb4 oci8ReturningOutBind(void *octxp, OCIBind *bindp, ub4 iter, ub4 index, void **bufpp, ub4 **alenp, ub1 *piecep, void **indp, ub2 **rcodep) {
+	oci8ReturningBind *returningBind = octxp;
+	*piecep = OCI_ONE_PIECE;
+
+	if (index == 0) {
+		// first call of the iteration, get the number of rows returned by the iteration
+		ub4 rows = 0;
+		if (OCIAttrGet(bindp, OCI_HTYPE_BIND, &rows, NULL, OCI_ATTR_ROWS_RETURNED, returningBind->errHandle) != OCI_SUCCESS) {
+			return OCI_ERROR;
+		}
+		returningBind->iterStart = returningBind->rows;
+		if (rows == 0) {
+			// nothing returned, OCI still needs buffers
+			returningBind->emptyLength = sizeof(returningBind->emptyBuffer);
+			*bufpp = &returningBind->emptyBuffer;
+			*alenp = &returningBind->emptyLength;
+			*indp = &returningBind->emptyIndicator;
+			*rcodep = &returningBind->emptyRcode;
+			return OCI_CONTINUE;
+		}
+		if (returningBind->rows + rows > returningBind->capacity && !oci8ReturningBindGrow(returningBind, returningBind->rows + rows)) {
+			return OCI_ERROR;
+		}
+		returningBind->rows += rows;
+	}
+
+	ub4 row = returningBind->iterStart + index;
+	if (row >= returningBind->rows) {
+		return OCI_ERROR;
+	}
+
+	if (returningBind->buffers[row] == NULL) {
+		void *buffer = calloc(1, returningBind->maxSize);
+		if (buffer == NULL) {
+			return OCI_ERROR;
+		}
+		if (returningBind->descriptorType != 0 &&
+			OCIDescriptorAlloc(returningBind->env, (void **)buffer, returningBind->descriptorType, 0, NULL) != OCI_SUCCESS) {
+			free(buffer);
+			return OCI_ERROR;
+		}
+		returningBind->buffers[row] = buffer;
+	}
+
+	returningBind->lengths[row] = returningBind->maxSize;
+	returningBind->indicators[row] = 0;
+	returningBind->rcodes[row] = 0;
+	*bufpp = returningBind->buffers[row];
+	*alenp = &returningBind->lengths[row];
+	*indp = &returningBind->indicators[row];
+	*rcodep = &returningBind->rcodes[row];
+	return OCI_CONTINUE;
+}
+
+// oci8ReturningBindDynamic calls OCIBindDynamic with the returning bind callbacks
+sword oci8ReturningBindDynamic(OCIBind *bindHandle, OCIError *errHandle, oci8ReturningBind *returningBind) {
+	return OCIBindDynamic(bindHandle, errHandle, returningBind, oci8ReturningInBind, returningBind, oci8ReturningOutBind);
+}
+
+// oci8ReturningBindFree frees the returning bind, the row buffers, and the row descriptors
+void oci8ReturningBindFree(oci8ReturningBind *returningBind) {
+	if (returningBind == NULL) {
+		return;
+	}
+	for (ub4 i = 0; i < returningBind->capacity; i++) {
+		if (returningBind->buffers[i] == NULL) {
+			continue;
+		}
+		if (returningBind->descriptorType != 0) {
+			OCIDescriptorFree(*(void **)returningBind->buffers[i], returningBind->descriptorType);
+		}
+		free(returningBind->buffers[i]);
+	}
+	free(returningBind->buffers);
+	free(returningBind->lengths);
+	free(returningBind->indicators);
+	free(returningBind->rcodes);
+	free(returningBind);
+}
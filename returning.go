@@ -0,0 +1,180 @@
+package oci8
+
+// #include "oci8.go.h"
+import "C"
+
+import (
+	"database/sql"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"reflect"
+	"unsafe"
+)
+
+var (
+	typeNullString  = reflect.TypeOf(sql.NullString{})
+	typeNullInt64   = reflect.TypeOf(sql.NullInt64{})
+	typeNullFloat64 = reflect.TypeOf(sql.NullFloat64{})
+	typeNullBool    = reflect.TypeOf(sql.NullBool{})
+)
+
+// isReturningDest returns true if dest is a pointer to a slice that is filled with one value for each row returned,
+// like for a RETURNING INTO clause. A pointer to []byte is a scalar out bind.
+func isReturningDest(dest interface{}) bool {
+	rv := reflect.ValueOf(dest)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Kind() != reflect.Slice {
+		return false
+	}
+	return rv.Elem().Type().Elem().Kind() != reflect.Uint8
+}
+
+// makeReturningBind makes a dynamic out bind for the slice element type of dest
+func (stmt *Stmt) makeReturningBind(sbind *bindStruct, dest interface{}) error {
+	dataType, maxSize, descriptorType, err := elementBindType(reflect.TypeOf(dest).Elem().Elem(), 32767)
+	if err != nil {
+		return err
+	}
+
+	sbind.returning = C.oci8ReturningBindNew(stmt.conn.env, stmt.conn.errHandle, dataType, C.ub4(maxSize), descriptorType)
+	if sbind.returning == nil {
+		return fmt.Errorf("allocate returning bind failed")
+	}
+	sbind.dataType = dataType
+	sbind.pbuf = nil
+	sbind.maxSize = C.sb4(maxSize)
+
+	return nil
+}
+
+// elementBindType returns the bind data type, max size, and descriptor type for values of a slice element type.
+// stringMaxSize is the max size of string and []byte values.
+func elementBindType(elemType reflect.Type, stringMaxSize int) (C.ub2, int, C.ub4, error) {
+	switch elemType {
+	case typeSliceByte:
+		return C.SQLT_BIN, stringMaxSize, 0, nil
+	case typeTime:
+		return C.SQLT_TIMESTAMP_TZ, int(sizeOfNilPointer), C.OCI_DTYPE_TIMESTAMP_TZ, nil
+	case typeJSONNumber, typeBigInt, typeBigRat:
+		return C.SQLT_NUM, numberMaxLength, 0, nil
+	case typeNullString:
+		return C.SQLT_CHR, stringMaxSize, 0, nil
+	case typeNullInt64, typeNullBool:
+		return C.SQLT_INT, 8, 0, nil
+	case typeNullFloat64:
+		return C.SQLT_BDOUBLE, 8, 0, nil
+	}
+
+	switch elemType.Kind() {
+	case reflect.String:
+		return C.SQLT_CHR, stringMaxSize, 0, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr, reflect.Bool:
+		return C.SQLT_INT, 8, 0, nil
+	case reflect.Float32, reflect.Float64:
+		return C.SQLT_BDOUBLE, 8, 0, nil
+	}
+
+	return 0, 0, 0, fmt.Errorf("unsupported element type %v", elemType)
+}
+
+// ociBindDynamic calls OCIBindDynamic with the returning bind callbacks
+func (stmt *Stmt) ociBindDynamic(bind *bindStruct) error {
+	result := C.oci8ReturningBindDynamic(bind.bindHandle, stmt.conn.errHandle, bind.returning)
+	return stmt.conn.getError(result)
+}
+
+// outputReturningBind sets the slice of the returning bind destination to the returned values
+func (stmt *Stmt) outputReturningBind(bind *bindStruct) error {
+	returning := bind.returning
+	rows := int(returning.rows)
+
+	sliceValue := reflect.ValueOf(bind.out.Dest).Elem()
+	elemType := sliceValue.Type().Elem()
+	values := reflect.MakeSlice(sliceValue.Type(), rows, rows)
+
+	if rows > 0 {
+		buffers := (*[1 << 28]unsafe.Pointer)(unsafe.Pointer(returning.buffers))[:rows:rows]
+		lengths := (*[1 << 28]C.ub4)(unsafe.Pointer(returning.lengths))[:rows:rows]
+		indicators := (*[1 << 28]C.sb2)(unsafe.Pointer(returning.indicators))[:rows:rows]
+
+		for i := 0; i < rows; i++ {
+			if indicators[i] == -1 {
+				// null, leave as zero value
+				continue
+			}
+			err := stmt.setElementValue(values.Index(i), elemType, returning.dataType, buffers[i], int(lengths[i]))
+			if err != nil {
+				return fmt.Errorf("returning row %v - error: %v", i, err)
+			}
+		}
+	}
+
+	sliceValue.Set(values)
+	return nil
+}
+
+// setElementValue sets a slice element value from a buffer of the bind data type
+func (stmt *Stmt) setElementValue(value reflect.Value, elemType reflect.Type, dataType C.ub2, buffer unsafe.Pointer, length int) error {
+	switch dataType {
+
+	case C.SQLT_CHR:
+		data := C.GoStringN((*C.char)(buffer), C.int(length))
+		if elemType == typeNullString {
+			value.Set(reflect.ValueOf(sql.NullString{String: data, Valid: true}))
+		} else {
+			value.SetString(data)
+		}
+
+	case C.SQLT_BIN:
+		value.SetBytes(C.GoBytes(buffer, C.int(length)))
+
+	case C.SQLT_INT:
+		switch {
+		case elemType == typeNullInt64:
+			value.Set(reflect.ValueOf(sql.NullInt64{Int64: getInt64(buffer), Valid: true}))
+		case elemType == typeNullBool:
+			value.Set(reflect.ValueOf(sql.NullBool{Bool: getInt64(buffer) != 0, Valid: true}))
+		case elemType.Kind() == reflect.Bool:
+			value.SetBool(getInt64(buffer) != 0)
+		case elemType.Kind() >= reflect.Uint && elemType.Kind() <= reflect.Uintptr:
+			value.SetUint(getUint64(buffer))
+		default:
+			value.SetInt(getInt64(buffer))
+		}
+
+	case C.SQLT_BDOUBLE:
+		data := math.Float64frombits(binary.LittleEndian.Uint64((*[8]byte)(buffer)[:]))
+		if elemType == typeNullFloat64 {
+			value.Set(reflect.ValueOf(sql.NullFloat64{Float64: data, Valid: true}))
+		} else {
+			value.SetFloat(data)
+		}
+
+	case C.SQLT_TIMESTAMP_TZ:
+		aTime, err := stmt.conn.ociDateTimeToTime(*(**C.OCIDateTime)(buffer), true)
+		if err != nil {
+			return err
+		}
+		value.Set(reflect.ValueOf(*aTime))
+
+	case C.SQLT_NUM:
+		numberType := numberTypeJSON
+		switch elemType {
+		case typeBigInt:
+			numberType = numberTypeBigInt
+		case typeBigRat:
+			numberType = numberTypeBigRat
+		}
+		number, err := decodeNumberAs((*[numberMaxLength]byte)(buffer)[:length], numberType)
+		if err != nil {
+			return err
+		}
+		value.Set(reflect.ValueOf(number))
+
+	default:
+		return fmt.Errorf("unhandled element data type %v", dataType)
+	}
+
+	return nil
+}
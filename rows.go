@@ -24,6 +24,11 @@ func (rows *Rows) Close() error {
 
 	freeDefines(rows.defines)
 
+	if rows.freeCursor {
+		C.OCIHandleFree(unsafe.Pointer(rows.stmt.stmt), C.OCI_HTYPE_STMT)
+		rows.stmt.stmt = nil
+	}
+
 	return nil
 }
 
@@ -46,35 +51,41 @@ func (rows *Rows) Next(dest []driver.Value) error {
 		return rows.stmt.ctx.Err()
 	}
 
-	done := make(chan struct{})
-	defer close(done)
-	go rows.stmt.conn.ociBreakDone(rows.stmt.ctx, done)
-	result := C.OCIStmtFetch2(
-		rows.stmt.stmt,
-		rows.stmt.conn.errHandle,
-		1,
-		C.OCI_FETCH_NEXT,
-		0,
-		C.OCI_DEFAULT)
-	if result == C.OCI_NO_DATA {
-		return io.EOF
-	} else if result != C.OCI_SUCCESS && result != C.OCI_SUCCESS_WITH_INFO {
-		return rows.stmt.conn.getError(result)
+	// fetch the next block of rows when all the fetched rows have been read
+	if rows.rowIndex >= rows.rowsFetched {
+		if rows.fetchDone {
+			return io.EOF
+		}
+		err := rows.fetch()
+		if err != nil {
+			return err
+		}
+		if rows.rowsFetched < 1 {
+			return io.EOF
+		}
 	}
 
+	row := rows.rowIndex
+	rows.rowIndex++
+
+	var result C.sword
 	for i := range dest {
-		if *rows.defines[i].indicator == -1 { // Null
+		pbuf := unsafe.Pointer(uintptr(rows.defines[i].pbuf) + uintptr(row)*uintptr(rows.defines[i].maxSize))
+		indicator := (*[1 << 28]C.sb2)(unsafe.Pointer(rows.defines[i].indicator))[row]
+		length := (*[1 << 28]C.ub2)(unsafe.Pointer(rows.defines[i].length))[row]
+
+		if indicator == -1 { // Null
 			dest[i] = nil
 			continue
-		} else if *rows.defines[i].indicator != 0 {
-			return fmt.Errorf("unknown indicator %d for column %s", *rows.defines[i].indicator, rows.defines[i].name)
+		} else if indicator != 0 {
+			return fmt.Errorf("unknown indicator %d for column %s", indicator, rows.defines[i].name)
 		}
 
 		switch rows.defines[i].dataType {
 
 		// SQLT_DAT
 		case C.SQLT_DAT: // for test, date are return as timestamp
-			buf := (*[1 << 30]byte)(rows.defines[i].pbuf)[0:*rows.defines[i].length]
+			buf := (*[1 << 30]byte)(pbuf)[0:length]
 			// TODO: Handle BCE dates (http://docs.oracle.com/cd/B12037_01/appdev.101/b10779/oci03typ.htm#438305)
 			// TODO: Handle timezones (http://docs.oracle.com/cd/B12037_01/appdev.101/b10779/oci03typ.htm#443601)
 			dest[i] = time.Date(
@@ -89,7 +100,7 @@ func (rows *Rows) Next(dest []driver.Value) error {
 
 		// SQLT_BLOB and SQLT_CLOB
 		case C.SQLT_BLOB, C.SQLT_CLOB:
-			lobLocator := (**C.OCILobLocator)(rows.defines[i].pbuf)
+			lobLocator := (**C.OCILobLocator)(pbuf)
 			buffer, err := rows.stmt.conn.ociLobRead(*lobLocator, C.SQLCS_IMPLICIT)
 			if err != nil {
 				return err
@@ -104,26 +115,34 @@ func (rows *Rows) Next(dest []driver.Value) error {
 
 		// SQLT_CHR, SQLT_STR, SQLT_AFC, SQLT_AVC, and SQLT_LNG
 		case C.SQLT_CHR, C.SQLT_STR, C.SQLT_AFC, C.SQLT_AVC, C.SQLT_LNG:
-			dest[i] = C.GoStringN((*C.char)(rows.defines[i].pbuf), C.int(*rows.defines[i].length))
+			dest[i] = C.GoStringN((*C.char)(pbuf), C.int(length))
 
 		// SQLT_BIN
 		case C.SQLT_BIN: // RAW
-			buf := (*[1 << 30]byte)(rows.defines[i].pbuf)[0:*rows.defines[i].length]
+			buf := (*[1 << 30]byte)(pbuf)[0:length]
 			dest[i] = buf
 
 		// SQLT_NUM
 		case C.SQLT_NUM: // NUMBER
-			buf := (*[21]byte)(rows.defines[i].pbuf)[0:*rows.defines[i].length]
-			dest[i] = buf
+			buf := (*[numberMaxLength]byte)(pbuf)[0:length]
+			if rows.defines[i].numberType == numberTypeFloat {
+				dest[i] = buf
+				continue
+			}
+			number, err := decodeNumberAs(buf, rows.defines[i].numberType)
+			if err != nil {
+				return fmt.Errorf("number decode for column %v - error: %v", rows.defines[i].name, err)
+			}
+			dest[i] = number
 
 		// SQLT_VNU
 		case C.SQLT_VNU: // VARNUM
-			buf := (*[22]byte)(rows.defines[i].pbuf)[0:*rows.defines[i].length]
+			buf := (*[22]byte)(pbuf)[0:length]
 			dest[i] = buf
 
 		// SQLT_INT
 		case C.SQLT_INT: // INT
-			buf := (*[8]byte)(rows.defines[i].pbuf)[0:*rows.defines[i].length]
+			buf := (*[8]byte)(pbuf)[0:length]
 			var data int64
 			err := binary.Read(bytes.NewReader(buf), binary.LittleEndian, &data)
 			if err != nil {
@@ -133,7 +152,7 @@ func (rows *Rows) Next(dest []driver.Value) error {
 
 		// SQLT_BDOUBLE
 		case C.SQLT_BDOUBLE: // native double
-			buf := (*[8]byte)(rows.defines[i].pbuf)[0:*rows.defines[i].length]
+			buf := (*[8]byte)(pbuf)[0:length]
 			var data float64
 			err := binary.Read(bytes.NewReader(buf), binary.LittleEndian, &data)
 			if err != nil {
@@ -141,9 +160,13 @@ func (rows *Rows) Next(dest []driver.Value) error {
 			}
 			dest[i] = data
 
+		// SQLT_BOL
+		case C.SQLT_BOL: // Oracle 23 BOOLEAN
+			dest[i] = *(*C.boolean)(pbuf) != C.FALSE
+
 		// SQLT_TIMESTAMP
 		case C.SQLT_TIMESTAMP:
-			aTime, err := rows.stmt.conn.ociDateTimeToTime(*(**C.OCIDateTime)(rows.defines[i].pbuf), false)
+			aTime, err := rows.stmt.conn.ociDateTimeToTime(*(**C.OCIDateTime)(pbuf), false)
 			if err != nil {
 				return fmt.Errorf("ociDateTimeToTime for column %v - error: %v", i, err)
 			}
@@ -151,7 +174,7 @@ func (rows *Rows) Next(dest []driver.Value) error {
 
 		// SQLT_TIMESTAMP_TZ and SQLT_TIMESTAMP_LTZ
 		case C.SQLT_TIMESTAMP_TZ, C.SQLT_TIMESTAMP_LTZ:
-			aTime, err := rows.stmt.conn.ociDateTimeToTime(*(**C.OCIDateTime)(rows.defines[i].pbuf), true)
+			aTime, err := rows.stmt.conn.ociDateTimeToTime(*(**C.OCIDateTime)(pbuf), true)
 			if err != nil {
 				return fmt.Errorf("ociDateTimeToTime for column %v - error: %v", i, err)
 			}
@@ -164,7 +187,7 @@ func (rows *Rows) Next(dest []driver.Value) error {
 			var minutes C.sb4
 			var seconds C.sb4
 			var fracSeconds C.sb4
-			interval := *(**C.OCIInterval)(rows.defines[i].pbuf)
+			interval := *(**C.OCIInterval)(pbuf)
 			result = C.OCIIntervalGetDaySecond(
 				unsafe.Pointer(rows.stmt.conn.env), // environment handle
 				rows.stmt.conn.errHandle,           // error handle
@@ -186,7 +209,7 @@ func (rows *Rows) Next(dest []driver.Value) error {
 		case C.SQLT_INTERVAL_YM:
 			var years C.sb4
 			var months C.sb4
-			interval := *(**C.OCIInterval)(rows.defines[i].pbuf)
+			interval := *(**C.OCIInterval)(pbuf)
 			result = C.OCIIntervalGetYearMonth(
 				unsafe.Pointer(rows.stmt.conn.env), // environment handle
 				rows.stmt.conn.errHandle,           // error handle
@@ -201,21 +224,49 @@ func (rows *Rows) Next(dest []driver.Value) error {
 
 		// SQLT_RSET - ref cursor
 		case C.SQLT_RSET:
-			stmtP := (**C.OCIStmt)(rows.defines[i].pbuf)
-			subStmt := &Stmt{conn: rows.stmt.conn, stmt: *stmtP, ctx: rows.stmt.ctx}
+			stmtP := (**C.OCIStmt)(pbuf)
+			subStmt := &Stmt{conn: rows.stmt.conn, stmt: *stmtP, ctx: rows.stmt.ctx, numberType: rows.numberType}
 			if rows.defines[i].subDefines == nil {
 				var err error
-				rows.defines[i].subDefines, err = subStmt.makeDefines()
+				rows.defines[i].subDefines, err = subStmt.makeDefines(int(rows.stmt.conn.fetchArraySize))
 				if err != nil {
 					return err
 				}
 			}
 			subRows := &Rows{
-				stmt:    subStmt,
-				defines: rows.defines[i].subDefines,
+				stmt:       subStmt,
+				defines:    rows.defines[i].subDefines,
+				numberType: rows.numberType,
+			}
+			if len(subRows.defines) > 0 {
+				subRows.fetchArraySize = subRows.defines[0].arraySize
 			}
 			dest[i] = subRows
 
+		// SQLT_JSON
+		case C.SQLT_JSON: // Oracle 21 JSON
+			value, err := rows.stmt.conn.ociJSONValue(*(**C.OCIJson)(pbuf))
+			if err != nil {
+				return fmt.Errorf("JSON for column %v - error: %v", i, err)
+			}
+			dest[i] = value
+
+		// SQLT_VEC
+		case C.SQLT_VEC: // Oracle 23 VECTOR
+			value, err := rows.stmt.conn.ociVectorValue(*(**C.OCIVector)(pbuf))
+			if err != nil {
+				return fmt.Errorf("vector for column %v - error: %v", i, err)
+			}
+			dest[i] = value
+
+		// SQLT_NTY - object or collection
+		case C.SQLT_NTY:
+			value, err := rows.defines[i].object.value()
+			if err != nil {
+				return fmt.Errorf("object for column %v - error: %v", i, err)
+			}
+			dest[i] = value
+
 		// default
 		default:
 			return fmt.Errorf("Unhandled column type: %d", rows.defines[i].dataType)
@@ -226,6 +277,110 @@ func (rows *Rows) Next(dest []driver.Value) error {
 	return nil
 }
 
+// fetch calls OCIStmtFetch2 to fetch the next block of rows into the define buffers
+func (rows *Rows) fetch() error {
+	fetchArraySize := rows.fetchArraySize
+	if fetchArraySize < 1 {
+		fetchArraySize = 1
+	}
+
+	rows.stmt.conn.callBegin(rows.stmt.ctx)
+	result := C.OCIStmtFetch2(
+		rows.stmt.stmt,           // the statement handle
+		rows.stmt.conn.errHandle, // an error handle
+		C.ub4(fetchArraySize),    // number of rows to be fetched from the current position
+		C.OCI_FETCH_NEXT,         // fetch orientation
+		0,                        // the offset for fetch orientations OCI_FETCH_ABSOLUTE and OCI_FETCH_RELATIVE
+		C.OCI_DEFAULT,            // mode
+	)
+	rows.stmt.conn.callEnd()
+
+	rows.rowIndex = 0
+	rows.rowsFetched = 0
+
+	// OCI_NO_DATA is returned when fewer rows than requested are fetched, those rows still need to be read
+	if result == C.OCI_NO_DATA {
+		rows.fetchDone = true
+	} else if result != C.OCI_SUCCESS && result != C.OCI_SUCCESS_WITH_INFO {
+		return rows.stmt.conn.getError(result)
+	}
+
+	if fetchArraySize == 1 {
+		if !rows.fetchDone {
+			rows.rowsFetched = 1
+		}
+		return nil
+	}
+
+	var rowsFetched C.ub4 // the number of rows fetched by the last fetch call
+	_, err := rows.stmt.ociAttrGet(unsafe.Pointer(&rowsFetched), C.OCI_ATTR_ROWS_FETCHED)
+	if err != nil {
+		return err
+	}
+	rows.rowsFetched = int(rowsFetched)
+
+	return nil
+}
+
+// HasNextResultSet returns true if there are more implicit result sets
+func (rows *Rows) HasNextResultSet() bool {
+	return rows.implicitResults > 0
+}
+
+// NextResultSet moves to the next implicit result set
+func (rows *Rows) NextResultSet() error {
+	if rows.closed || rows.implicitResults < 1 {
+		return io.EOF
+	}
+	return rows.nextImplicitResult()
+}
+
+// nextImplicitResult calls OCIStmtGetNextResult to get the next implicit result set of the PL/SQL statement,
+// then makes the defines for it. The implicit result statement handles are freed with the PL/SQL statement handle.
+func (rows *Rows) nextImplicitResult() error {
+	implicitStmt := rows.implicitStmt
+	var resultP unsafe.Pointer
+	var resultType C.ub4
+	result := C.OCIStmtGetNextResult(
+		implicitStmt.stmt,           // statement handle of the executed PL/SQL
+		implicitStmt.conn.errHandle, // error handle
+		&resultP,                    // the next implicit result statement handle
+		&resultType,                 // the result type, only OCI_RESULT_TYPE_SELECT
+		C.OCI_DEFAULT,               // mode
+	)
+	if result == C.OCI_NO_DATA {
+		rows.implicitResults = 0
+		return io.EOF
+	}
+	err := implicitStmt.conn.getError(result)
+	if err != nil {
+		return err
+	}
+	if resultType != C.OCI_RESULT_TYPE_SELECT {
+		return fmt.Errorf("unknown implicit result type %v", resultType)
+	}
+	rows.implicitResults--
+
+	stmt := &Stmt{conn: implicitStmt.conn, stmt: (*C.OCIStmt)(resultP), ctx: implicitStmt.ctx, numberType: rows.numberType}
+	defines, err := stmt.makeDefines(int(stmt.conn.fetchArraySize))
+	if err != nil {
+		return err
+	}
+
+	freeDefines(rows.defines)
+	rows.stmt = stmt
+	rows.defines = defines
+	rows.fetchArraySize = 0
+	if len(defines) > 0 {
+		rows.fetchArraySize = defines[0].arraySize
+	}
+	rows.rowsFetched = 0
+	rows.rowIndex = 0
+	rows.fetchDone = false
+
+	return nil
+}
+
 // ColumnTypeDatabaseTypeName implement RowsColumnTypeDatabaseTypeName.
 func (rows *Rows) ColumnTypeDatabaseTypeName(i int) string {
 	if len(rows.defines) < i+1 {
@@ -275,6 +430,12 @@ func (rows *Rows) ColumnTypeDatabaseTypeName(i int) string {
 		return "SQLT_RDD"
 	case C.SQLT_NTY:
 		return "SQLT_NTY"
+	case C.SQLT_BOL:
+		return "SQLT_BOL"
+	case C.SQLT_JSON:
+		return "SQLT_JSON"
+	case C.SQLT_VEC:
+		return "SQLT_VEC"
 	case C.SQLT_REF:
 		return "SQLT_REF"
 	case C.SQLT_CLOB:
@@ -330,12 +491,48 @@ func (rows *Rows) ColumnTypeScanType(i int) reflect.Type {
 		return typeSliceByte
 	case C.SQLT_INT:
 		return typeInt64
-	case C.SQLT_BDOUBLE, C.SQLT_IBDOUBLE, C.SQLT_BFLOAT, C.SQLT_IBFLOAT, C.SQLT_NUM:
+	case C.SQLT_NUM:
+		switch rows.defines[i].numberType {
+		case numberTypeString:
+			return typeString
+		case numberTypeJSON:
+			return typeJSONNumber
+		case numberTypeBigInt:
+			return typeBigInt
+		case numberTypeBigRat:
+			return typeBigRat
+		}
+		return typeFloat64
+	case C.SQLT_BDOUBLE, C.SQLT_IBDOUBLE, C.SQLT_BFLOAT, C.SQLT_IBFLOAT:
 		return typeFloat64
 	case C.SQLT_TIMESTAMP, C.SQLT_DAT, C.SQLT_TIMESTAMP_TZ, C.SQLT_TIMESTAMP_LTZ:
 		return typeTime
 	case C.SQLT_INTERVAL_DS, C.SQLT_INTERVAL_YM:
 		return typeInt64
+	case C.SQLT_BOL:
+		return typeBool
+	case C.SQLT_JSON:
+		return typeJSON
+	case C.SQLT_VEC:
+		if rows.defines[i].vectorSparse {
+			return typeSparseVector
+		}
+		switch rows.defines[i].vectorFormat {
+		case C.OCI_ATTR_VECTOR_FORMAT_FLOAT32:
+			return typeSliceFloat32
+		case C.OCI_ATTR_VECTOR_FORMAT_FLOAT64:
+			return typeSliceFloat64
+		case C.OCI_ATTR_VECTOR_FORMAT_INT8:
+			return typeSliceInt8
+		case C.OCI_ATTR_VECTOR_FORMAT_BINARY:
+			return typeSliceByte
+		}
+		return typeInterface
+	case C.SQLT_NTY:
+		if rows.defines[i].object != nil && rows.defines[i].object.objectType.typeCode == C.OCI_TYPECODE_NAMEDCOLLECTION {
+			return typeCollection
+		}
+		return typeObject
 	}
 
 	return typeNil
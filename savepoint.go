@@ -0,0 +1,113 @@
+package oci8
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+)
+
+// CurrentTx returns the transaction in progress on the connection, or nil if there is none.
+// Use it with sql.Conn.Raw to get to the savepoint methods of a transaction.
+func (conn *Conn) CurrentTx() *Tx {
+	return conn.tx
+}
+
+// Savepoint creates a savepoint with name in the transaction.
+// Creating a savepoint with the name of an existing savepoint moves it to the top of the savepoint stack.
+func (tx *Tx) Savepoint(name string) error {
+	name, err := tx.checkSavepoint(name)
+	if err != nil {
+		return err
+	}
+
+	err = tx.conn.execQuery(context.Background(), "savepoint "+name)
+	if err != nil {
+		return err
+	}
+
+	index := tx.savepointIndex(name)
+	if index >= 0 {
+		tx.savepoints = append(tx.savepoints[:index], tx.savepoints[index+1:]...)
+	}
+	tx.savepoints = append(tx.savepoints, name)
+
+	return nil
+}
+
+// RollbackTo rolls back the transaction to the savepoint with name.
+// The savepoint is kept, the savepoints created after it are removed.
+func (tx *Tx) RollbackTo(name string) error {
+	name, err := tx.checkSavepoint(name)
+	if err != nil {
+		return err
+	}
+
+	index := tx.savepointIndex(name)
+	if index < 0 {
+		return fmt.Errorf("savepoint %v does not exist", name)
+	}
+
+	err = tx.conn.execQuery(context.Background(), "rollback to savepoint "+name)
+	if err != nil {
+		return err
+	}
+
+	tx.savepoints = tx.savepoints[:index+1]
+
+	return nil
+}
+
+// Release removes the savepoint with name and the savepoints created after it.
+// Oracle does not have release savepoint, so the changes since the savepoint stay part of the transaction.
+func (tx *Tx) Release(name string) error {
+	name, err := tx.checkSavepoint(name)
+	if err != nil {
+		return err
+	}
+
+	index := tx.savepointIndex(name)
+	if index < 0 {
+		return fmt.Errorf("savepoint %v does not exist", name)
+	}
+
+	tx.savepoints = tx.savepoints[:index]
+
+	return nil
+}
+
+// Savepoints returns the names of the savepoints in the transaction, oldest first
+func (tx *Tx) Savepoints() []string {
+	savepoints := make([]string, len(tx.savepoints))
+	copy(savepoints, tx.savepoints)
+	return savepoints
+}
+
+// checkSavepoint checks that the transaction is in progress and that name is a valid savepoint name.
+// Returns the name in upper case, the same as Oracle stores unquoted identifiers.
+func (tx *Tx) checkSavepoint(name string) (string, error) {
+	if tx.conn.tx != tx {
+		return "", sql.ErrTxDone
+	}
+
+	if len(name) < 1 || len(name) > 128 || !isLetterByte(name[0]) {
+		return "", fmt.Errorf("invalid savepoint name: %q", name)
+	}
+	for i := 1; i < len(name); i++ {
+		if !isIdentifierByte(name[i]) || name[i] >= 0x80 {
+			return "", fmt.Errorf("invalid savepoint name: %q", name)
+		}
+	}
+
+	return strings.ToUpper(name), nil
+}
+
+// savepointIndex returns the index of the savepoint with name in the savepoint stack, or -1 if not found
+func (tx *Tx) savepointIndex(name string) int {
+	for i := len(tx.savepoints) - 1; i >= 0; i-- {
+		if tx.savepoints[i] == name {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,418 @@
+package oci8
+
+// #include "oci8.go.h"
+import "C"
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+	"unsafe"
+)
+
+// sessionPoolKey returns the key of the session pool for the DSN.
+// The key is a hash so the password is not kept in the map.
+func sessionPoolKey(dsn *DSN) string {
+	key := sha256.Sum256([]byte(fmt.Sprintf("%s\x00%s\x00%s\x00%d\x00%d\x00%d\x00%d\x00%d\x00%d",
+		dsn.Username, dsn.Password, dsn.Connect, dsn.poolMin, dsn.poolMax, dsn.poolIncrement, dsn.poolGetTimeout, dsn.poolIdleTimeout, dsn.stmtCacheSize)))
+	return hex.EncodeToString(key[:])
+}
+
+// getSessionPool returns the session pool for the DSN, creating it if needed.
+// releaseSessionPool must be called when the connection is done with the pool.
+func getSessionPool(dsn *DSN) (*sessionPool, error) {
+	key := sessionPoolKey(dsn)
+
+	sessionPoolsMutex.Lock()
+	defer sessionPoolsMutex.Unlock()
+
+	pool, ok := sessionPools[key]
+	if ok {
+		if pool.destroyTimer != nil {
+			pool.destroyTimer.Stop()
+			pool.destroyTimer = nil
+		}
+		pool.references++
+		return pool, nil
+	}
+
+	pool, err := newSessionPool(dsn)
+	if err != nil {
+		return nil, err
+	}
+	pool.key = key
+	pool.idleTimeout = dsn.poolIdleTimeout
+	pool.references = 1
+	sessionPools[key] = pool
+
+	return pool, nil
+}
+
+// releaseSessionPool releases a reference to the session pool.
+// A pool without references is kept so the next connection does not create it again.
+// It is destroyed after the pool idle timeout, or right away if CloseSessionPool was called.
+func releaseSessionPool(pool *sessionPool) {
+	sessionPoolsMutex.Lock()
+	defer sessionPoolsMutex.Unlock()
+
+	pool.references--
+	if pool.references > 0 {
+		return
+	}
+
+	if sessionPools[pool.key] != pool {
+		// closed by CloseSessionPool
+		pool.destroyLocked()
+		return
+	}
+
+	if pool.idleTimeout > 0 {
+		pool.destroyTimer = time.AfterFunc(pool.idleTimeout, func() {
+			sessionPoolsMutex.Lock()
+			defer sessionPoolsMutex.Unlock()
+			if pool.references > 0 || sessionPools[pool.key] != pool {
+				// used again or closed before the timer func got the lock
+				return
+			}
+			delete(sessionPools, pool.key)
+			pool.destroyLocked()
+		})
+	}
+}
+
+// destroyLocked destroys the session pool while holding the pool mutex, so stats does not use a destroyed pool
+func (pool *sessionPool) destroyLocked() {
+	pool.mutex.Lock()
+	defer pool.mutex.Unlock()
+	pool.destroy()
+}
+
+// newSessionPool creates an environment and calls OCISessionPoolCreate
+func newSessionPool(dsn *DSN) (*sessionPool, error) {
+	env, err := ociEnvCreate()
+	if err != nil {
+		return nil, err
+	}
+
+	// a conn for the pool environment and error handle, so the conn oci helpers can be used
+	conn := &Conn{env: env}
+
+	var handleTemp unsafe.Pointer
+	handle := &handleTemp
+	result := C.OCIHandleAlloc(
+		unsafe.Pointer(env), // An environment handle
+		handle,              // Returns a handle
+		C.OCI_HTYPE_ERROR,   // type of handle: https://docs.oracle.com/cd/B28359_01/appdev.111/b28395/oci02bas.htm#LNOCI87581
+		0,                   // amount of user memory to be allocated
+		nil,                 // Returns a pointer to the user memory
+	)
+	if result != C.OCI_SUCCESS {
+		C.OCIHandleFree(unsafe.Pointer(env), C.OCI_HTYPE_ENV)
+		return nil, errors.New("allocate error handle error")
+	}
+	conn.errHandle = (*C.OCIError)(*handle)
+
+	pool := &sessionPool{
+		env:       env,
+		errHandle: conn.errHandle,
+	}
+
+	handle, _, err = conn.ociHandleAlloc(C.OCI_HTYPE_SPOOL, 0)
+	if err != nil {
+		pool.free()
+		return nil, fmt.Errorf("allocate session pool handle error: %v", err)
+	}
+	pool.pool = (*C.OCISPool)(*handle)
+
+	connectString := cString(dsn.Connect)
+	defer C.free(unsafe.Pointer(connectString))
+	username := cString(dsn.Username)
+	defer C.free(unsafe.Pointer(username))
+	password := cString(dsn.Password)
+	defer C.free(unsafe.Pointer(password))
+
+	mode := C.ub4(C.OCI_DEFAULT)
+	if len(dsn.Username) > 0 {
+		// all sessions use the same username and password
+		mode = C.OCI_SPC_HOMOGENEOUS
+	}
+	if dsn.stmtCacheSize > 0 {
+		mode |= C.OCI_SPC_STMTCACHE
+	}
+
+	result = C.OCISessionPoolCreate(
+		env,                      // environment handle
+		pool.errHandle,           // error handle
+		pool.pool,                // session pool handle
+		&pool.name,               // returns the name of the session pool, used with OCISessionGet
+		&pool.nameLength,         // returns the length of the name of the session pool
+		connectString,            // connect string
+		C.ub4(len(dsn.Connect)),  // length of the connect string
+		dsn.poolMin,              // min number of sessions
+		dsn.poolMax,              // max number of sessions
+		dsn.poolIncrement,        // number of sessions opened when more are needed
+		username,                 // pool username
+		C.ub4(len(dsn.Username)), // length of pool username
+		password,                 // pool password
+		C.ub4(len(dsn.Password)), // length of pool password
+		mode,                     // mode of operation
+	)
+	if result != C.OCI_SUCCESS && result != C.OCI_SUCCESS_WITH_INFO {
+		err = conn.getError(result)
+		pool.free()
+		return nil, err
+	}
+
+	getMode := C.ub1(C.OCI_SPOOL_ATTRVAL_WAIT)
+	if dsn.poolGetTimeout > 0 {
+		getMode = C.OCI_SPOOL_ATTRVAL_TIMEDWAIT
+		waitTimeout := C.ub4(dsn.poolGetTimeout / time.Millisecond)
+		err = conn.ociAttrSet(unsafe.Pointer(pool.pool), C.OCI_HTYPE_SPOOL, unsafe.Pointer(&waitTimeout), 0, C.OCI_ATTR_SPOOL_WAIT_TIMEOUT)
+		if err != nil {
+			pool.destroy()
+			return nil, fmt.Errorf("session pool wait timeout attribute set error: %v", err)
+		}
+	}
+	err = conn.ociAttrSet(unsafe.Pointer(pool.pool), C.OCI_HTYPE_SPOOL, unsafe.Pointer(&getMode), 0, C.OCI_ATTR_SPOOL_GETMODE)
+	if err != nil {
+		pool.destroy()
+		return nil, fmt.Errorf("session pool get mode attribute set error: %v", err)
+	}
+
+	if dsn.stmtCacheSize > 0 {
+		stmtCacheSize := dsn.stmtCacheSize
+		err = conn.ociAttrSet(unsafe.Pointer(pool.pool), C.OCI_HTYPE_SPOOL, unsafe.Pointer(&stmtCacheSize), 0, C.OCI_ATTR_SPOOL_STMTCACHESIZE)
+		if err != nil {
+			pool.destroy()
+			return nil, fmt.Errorf("session pool statement cache size attribute set error: %v", err)
+		}
+	}
+
+	if dsn.poolIdleTimeout > 0 {
+		idleTimeout := C.ub4(dsn.poolIdleTimeout / time.Second)
+		err = conn.ociAttrSet(unsafe.Pointer(pool.pool), C.OCI_HTYPE_SPOOL, unsafe.Pointer(&idleTimeout), 0, C.OCI_ATTR_SPOOL_TIMEOUT)
+		if err != nil {
+			pool.destroy()
+			return nil, fmt.Errorf("session pool timeout attribute set error: %v", err)
+		}
+	}
+
+	return pool, nil
+}
+
+// destroy calls OCISessionPoolDestroy then frees the handles
+func (pool *sessionPool) destroy() {
+	C.OCISessionPoolDestroy(pool.pool, pool.errHandle, C.OCI_SPD_FORCE)
+	pool.free()
+}
+
+// free frees the session pool handles
+func (pool *sessionPool) free() {
+	if pool.pool != nil {
+		C.OCIHandleFree(unsafe.Pointer(pool.pool), C.OCI_HTYPE_SPOOL)
+		pool.pool = nil
+	}
+	C.OCIHandleFree(unsafe.Pointer(pool.errHandle), C.OCI_HTYPE_ERROR)
+	C.OCIHandleFree(unsafe.Pointer(pool.env), C.OCI_HTYPE_ENV)
+	pool.errHandle = nil
+	pool.env = nil
+}
+
+// stats returns the session pool statistics
+func (pool *sessionPool) stats() (*SessionPoolStats, error) {
+	pool.mutex.Lock()
+	defer pool.mutex.Unlock()
+
+	if pool.pool == nil {
+		return nil, errors.New("session pool has been destroyed")
+	}
+
+	conn := &Conn{env: pool.env, errHandle: pool.errHandle}
+	attributes := []C.ub4{C.OCI_ATTR_SPOOL_OPEN_COUNT, C.OCI_ATTR_SPOOL_BUSY_COUNT, C.OCI_ATTR_SPOOL_MIN, C.OCI_ATTR_SPOOL_MAX, C.OCI_ATTR_SPOOL_INCR}
+	values := make([]int, len(attributes))
+	for i, attribute := range attributes {
+		var value C.ub4
+		result := C.OCIAttrGet(
+			unsafe.Pointer(pool.pool), // session pool handle
+			C.OCI_HTYPE_SPOOL,         // handle type
+			unsafe.Pointer(&value),    // attribute value
+			nil,                       // size of the attribute value
+			attribute,                 // attribute type
+			pool.errHandle,            // an error handle
+		)
+		err := conn.getError(result)
+		if err != nil {
+			return nil, err
+		}
+		values[i] = int(value)
+	}
+
+	return &SessionPoolStats{
+		Open:      values[0],
+		Busy:      values[1],
+		Min:       values[2],
+		Max:       values[3],
+		Increment: values[4],
+	}, nil
+}
+
+// GetSessionPoolStats returns the statistics of the session pool used by the DSN
+func GetSessionPoolStats(dsnString string) (*SessionPoolStats, error) {
+	dsn, err := ParseDSN(dsnString)
+	if err != nil {
+		return nil, err
+	}
+	if !dsn.sessionPool {
+		return nil, errors.New("dsn does not use a session pool")
+	}
+
+	sessionPoolsMutex.Lock()
+	pool, ok := sessionPools[sessionPoolKey(dsn)]
+	sessionPoolsMutex.Unlock()
+	if !ok {
+		return nil, errors.New("session pool has not been created")
+	}
+
+	return pool.stats()
+}
+
+// CloseSessionPool closes the session pool used by the DSN.
+// The pool is destroyed when the connections using it are closed, new connections create a new pool.
+func CloseSessionPool(dsnString string) error {
+	dsn, err := ParseDSN(dsnString)
+	if err != nil {
+		return err
+	}
+	if !dsn.sessionPool {
+		return errors.New("dsn does not use a session pool")
+	}
+
+	sessionPoolsMutex.Lock()
+	defer sessionPoolsMutex.Unlock()
+
+	key := sessionPoolKey(dsn)
+	pool, ok := sessionPools[key]
+	if !ok {
+		return errors.New("session pool has not been created")
+	}
+	delete(sessionPools, key)
+	if pool.destroyTimer != nil {
+		pool.destroyTimer.Stop()
+		pool.destroyTimer = nil
+	}
+	if pool.references < 1 {
+		pool.destroyLocked()
+	}
+
+	return nil
+}
+
+// sessionPoolGet gets a session from the session pool for the DSN by calling OCISessionGet
+func (conn *Conn) sessionPoolGet(dsn *DSN) error {
+	pool, err := getSessionPool(dsn)
+	if err != nil {
+		return err
+	}
+
+	conn.env = pool.env
+	conn.sessionPool = pool
+
+	var handleTemp unsafe.Pointer
+	handle := &handleTemp
+	result := C.OCIHandleAlloc(
+		unsafe.Pointer(conn.env), // An environment handle
+		handle,                   // Returns a handle
+		C.OCI_HTYPE_ERROR,        // type of handle: https://docs.oracle.com/cd/B28359_01/appdev.111/b28395/oci02bas.htm#LNOCI87581
+		0,                        // amount of user memory to be allocated
+		nil,                      // Returns a pointer to the user memory
+	)
+	if result != C.OCI_SUCCESS {
+		conn.sessionPoolFree()
+		return errors.New("allocate error handle error")
+	}
+	conn.errHandle = (*C.OCIError)(*handle)
+
+	// a pool without a username is not homogeneous, the sessions use external credentials
+	mode := C.ub4(C.OCI_SESSGET_SPOOL)
+	if len(dsn.Username) < 1 {
+		mode |= C.OCI_SESSGET_CREDEXT
+	}
+
+	// auth info for the Database Resident Connection Pooling (DRCP) connection class and purity
+	var authInfo *C.OCIAuthInfo
+	if len(dsn.connectionClass) > 0 || dsn.purity != C.OCI_ATTR_PURITY_DEFAULT {
+		handle, _, err = conn.ociHandleAlloc(C.OCI_HTYPE_AUTHINFO, 0)
+		if err != nil {
+			conn.sessionPoolFree()
+			return fmt.Errorf("allocate auth info handle error: %v", err)
+		}
+		authInfo = (*C.OCIAuthInfo)(*handle)
+		defer C.OCIHandleFree(unsafe.Pointer(authInfo), C.OCI_HTYPE_AUTHINFO)
+
+		err = conn.setSessionDRCP(unsafe.Pointer(authInfo), C.OCI_HTYPE_AUTHINFO, dsn)
+		if err != nil {
+			conn.sessionPoolFree()
+			return err
+		}
+	}
+
+	result = C.OCISessionGet(
+		conn.env,        // environment handle
+		conn.errHandle,  // error handle
+		&conn.svc,       // returns the service context
+		authInfo,        // auth info, only used for the DRCP connection class and purity
+		pool.name,       // session pool name
+		pool.nameLength, // length of session pool name
+		nil,             // tag info
+		0,               // length of tag info
+		nil,             // returns tag info
+		nil,             // returns length of tag info
+		nil,             // returns if a session with the tag was found
+		mode,            // mode: OCI_SESSGET_SPOOL gets a session from a session pool, OCI_SESSGET_CREDEXT uses external credentials
+	)
+	if result != C.OCI_SUCCESS && result != C.OCI_SUCCESS_WITH_INFO {
+		err = conn.getError(result)
+		conn.sessionPoolFree()
+		return err
+	}
+
+	return nil
+}
+
+// sessionPoolFree frees the connection error handle and releases the session pool
+func (conn *Conn) sessionPoolFree() {
+	if conn.errHandle != nil {
+		C.OCIHandleFree(unsafe.Pointer(conn.errHandle), C.OCI_HTYPE_ERROR)
+	}
+	conn.svc = nil
+	conn.errHandle = nil
+	conn.env = nil
+	releaseSessionPool(conn.sessionPool)
+	conn.sessionPool = nil
+}
+
+// sessionPoolRelease releases the session back to the session pool by calling OCISessionRelease.
+// The session is dropped instead if the connection is bad.
+func (conn *Conn) sessionPoolRelease() error {
+	mode := C.ub4(C.OCI_DEFAULT)
+	if conn.badConn {
+		mode = C.OCI_SESSRLS_DROPSESS
+	}
+
+	var err error
+	if rv := C.OCISessionRelease(
+		conn.svc,       // service context
+		conn.errHandle, // error handle
+		nil,            // tag
+		0,              // length of tag
+		mode,           // mode: OCI_DEFAULT or OCI_SESSRLS_DROPSESS
+	); rv != C.OCI_SUCCESS {
+		err = conn.getError(rv)
+	}
+
+	conn.sessionPoolFree()
+
+	return err
+}
@@ -9,7 +9,12 @@ import (
 	"database/sql"
 	"database/sql/driver"
 	"encoding/binary"
+	"encoding/json"
 	"fmt"
+	"math"
+	"math/big"
+	"reflect"
+	"strconv"
 	"strings"
 	"time"
 	"unsafe"
@@ -22,12 +27,18 @@ func (stmt *Stmt) Close() error {
 	}
 	stmt.closed = true
 
+	var key *C.OraText
+	if len(stmt.cacheKey) > 0 {
+		key = cString(stmt.cacheKey)
+		defer C.free(unsafe.Pointer(key))
+	}
+
 	result := C.OCIStmtRelease(
-		stmt.stmt,            // statement handle
-		stmt.conn.errHandle,  // error handle
-		nil,                  // key to be associated with the statement in the cache
-		C.ub4(0),             // length of the key
-		C.ub4(C.OCI_DEFAULT), // mode
+		stmt.stmt,                 // statement handle
+		stmt.conn.errHandle,       // error handle
+		key,                       // key to be associated with the statement in the cache
+		C.ub4(len(stmt.cacheKey)), // length of the key
+		C.ub4(C.OCI_DEFAULT),      // mode
 	)
 	stmt.stmt = nil
 
@@ -36,6 +47,11 @@ func (stmt *Stmt) Close() error {
 
 // NumInput returns the number of input
 func (stmt *Stmt) NumInput() int {
+	if stmt.ordinalBinds > 0 {
+		// $N placeholders can be repeated, so the bind count can be more than the number of arguments
+		return stmt.ordinalBinds
+	}
+
 	var bindCount C.ub4 // number of bind position
 	_, err := stmt.ociAttrGet(unsafe.Pointer(&bindCount), C.OCI_ATTR_BIND_COUNT)
 	if err != nil {
@@ -47,13 +63,88 @@ func (stmt *Stmt) NumInput() int {
 
 // CheckNamedValue checks a named value
 func (stmt *Stmt) CheckNamedValue(namedValue *driver.NamedValue) error {
-	switch namedValue.Value.(type) {
+	err := stmt.checkNamedValue(namedValue)
+	if err != nil && err != driver.ErrSkip && err != driver.ErrRemoveArgument {
+		// the statement is not executed, so the options of the earlier values are not used
+		stmt.resetOptions()
+	}
+	return err
+}
+
+// checkNamedValue checks a named value for CheckNamedValue
+func (stmt *Stmt) checkNamedValue(namedValue *driver.NamedValue) error {
+	switch value := namedValue.Value.(type) {
 	case sql.Out:
 		return nil
+	case ArrayDMLOption:
+		switch value {
+		case BatchErrors:
+			stmt.batchErrors = true
+		case PLSQLArrays:
+			stmt.plsqlArrays = true
+		default:
+			return fmt.Errorf("unknown array DML option %d", value)
+		}
+		return driver.ErrRemoveArgument
+	case *DMLRowCounts:
+		stmt.dmlRowCounts = value
+		return driver.ErrRemoveArgument
+	case NumberType:
+		_, err := value.numberType()
+		if err != nil {
+			return err
+		}
+		stmt.numberType = value
+		return driver.ErrRemoveArgument
+	case json.Number, *big.Int, *big.Rat:
+		return nil
+	case Object:
+		return nil
+	case ObjectStruct:
+		object, err := value.object()
+		if err != nil {
+			return err
+		}
+		namedValue.Value = *object
+		return nil
+	case Collection:
+		return nil
+	case CollectionSlice:
+		collection, err := value.collection()
+		if err != nil {
+			return err
+		}
+		namedValue.Value = *collection
+		return nil
+	case Boolean:
+		return nil
+	case JSON:
+		return nil
+	case Vector, SparseVector:
+		return nil
+	case XMLType:
+		return nil
+	}
+	if isJSONValue(namedValue.Value) {
+		namedValue.Value = JSON{Value: namedValue.Value}
+		return nil
+	}
+	if isArrayBind(namedValue.Value) {
+		return nil
 	}
 	return driver.ErrSkip
 }
 
+// isArrayBind returns true if value is a Go slice that should be bound as an OCI bind array.
+// []byte and types implementing driver.Valuer are bound as scalars.
+func isArrayBind(value interface{}) bool {
+	if _, ok := value.(driver.Valuer); ok {
+		return false
+	}
+	rv := reflect.ValueOf(value)
+	return rv.Kind() == reflect.Slice && rv.Type().Elem().Kind() != reflect.Uint8
+}
+
 // bindValues binds the values to the stmt
 func (stmt *Stmt) bindValues(values []driver.Value, namedValues []driver.NamedValue) ([]bindStruct, error) {
 	if len(values) == 0 && len(namedValues) == 0 {
@@ -91,7 +182,74 @@ func (stmt *Stmt) bindValues(values []driver.Value, namedValues []driver.NamedVa
 		var isOut bool
 		var isNill bool
 		sbind.out, isOut = valueInterface.(sql.Out)
-		if isOut {
+		if isOut && stmt.plsqlArrays && isReturningDest(sbind.out.Dest) {
+			// OUT or IN OUT PL/SQL associative array
+			err = stmt.makePLSQLArrayBind(&sbind, reflect.ValueOf(sbind.out.Dest).Elem(), true, sbind.out.In)
+			if err != nil {
+				binds = append(binds, sbind)
+				freeBinds(binds)
+				return nil, fmt.Errorf("PL/SQL array bind for column %v - error: %v", i, err)
+			}
+			valueInterface = sbind.plsqlArrayCurrent
+		} else if isOut && isReturningDest(sbind.out.Dest) {
+			// slice destination for a RETURNING INTO clause, one value for each row
+			err = stmt.makeReturningBind(&sbind, sbind.out.Dest)
+			if err != nil {
+				binds = append(binds, sbind)
+				freeBinds(binds)
+				return nil, fmt.Errorf("returning bind for column %v - error: %v", i, err)
+			}
+			valueInterface = sbind.returning
+		} else if dest, ok := sbind.out.Dest.(*Object); isOut && ok {
+			// object out bind
+			valueInterface = dest
+		} else if dest, ok := sbind.out.Dest.(*ObjectStruct); isOut && ok {
+			// object out bind
+			valueInterface, err = dest.object()
+			if err != nil {
+				binds = append(binds, sbind)
+				freeBinds(binds)
+				return nil, fmt.Errorf("object for column %v - error: %v", i, err)
+			}
+		} else if dest, ok := sbind.out.Dest.(*Collection); isOut && ok {
+			// collection out bind
+			valueInterface = dest
+		} else if dest, ok := sbind.out.Dest.(*CollectionSlice); isOut && ok {
+			// collection out bind
+			valueInterface, err = dest.collection()
+			if err != nil {
+				binds = append(binds, sbind)
+				freeBinds(binds)
+				return nil, fmt.Errorf("collection for column %v - error: %v", i, err)
+			}
+		} else if dest, ok := sbind.out.Dest.(*Boolean); isOut && ok {
+			// native boolean out bind
+			valueInterface = Boolean{}
+			if sbind.out.In {
+				valueInterface = *dest
+			}
+		} else if dest, ok := sbind.out.Dest.(*JSON); isOut && ok {
+			// JSON out bind
+			valueInterface = JSON{}
+			if sbind.out.In {
+				valueInterface = *dest
+			}
+		} else if dest, ok := sbind.out.Dest.(*Vector); isOut && ok {
+			// vector out bind
+			valueInterface = Vector{}
+			if sbind.out.In {
+				valueInterface = *dest
+			}
+		} else if dest, ok := sbind.out.Dest.(*SparseVector); isOut && ok {
+			// sparse vector out bind
+			valueInterface = SparseVector{}
+			if sbind.out.In {
+				valueInterface = *dest
+			}
+		} else if _, ok := sbind.out.Dest.(*driver.Rows); isOut && ok {
+			// ref cursor out bind
+			valueInterface = sbind.out.Dest
+		} else if isOut {
 			valueInterface, err = driver.DefaultParameterConverter.ConvertValue(sbind.out.Dest)
 			if err != nil {
 				binds = append(binds, sbind)
@@ -117,6 +275,81 @@ func (stmt *Stmt) bindValues(values []driver.Value, namedValues []driver.NamedVa
 
 		switch value := valueInterface.(type) {
 
+		case *C.oci8ReturningBind:
+			// dynamic out bind made by makeReturningBind
+
+		case *C.ub4:
+			// PL/SQL associative array made by makePLSQLArrayBind
+
+		case Object:
+			err = stmt.makeObjectBind(&sbind, value.TypeName, &value, false)
+			if err != nil {
+				binds = append(binds, sbind)
+				freeBinds(binds)
+				return nil, fmt.Errorf("object for column %v - error: %v", i, err)
+			}
+
+		case *Object:
+			err = stmt.makeObjectBind(&sbind, value.TypeName, value, isOut && !sbind.out.In)
+			if err != nil {
+				binds = append(binds, sbind)
+				freeBinds(binds)
+				return nil, fmt.Errorf("object for column %v - error: %v", i, err)
+			}
+
+		case Collection:
+			err = stmt.makeObjectBind(&sbind, value.TypeName, &value, false)
+			if err != nil {
+				binds = append(binds, sbind)
+				freeBinds(binds)
+				return nil, fmt.Errorf("collection for column %v - error: %v", i, err)
+			}
+
+		case *Collection:
+			err = stmt.makeObjectBind(&sbind, value.TypeName, value, isOut && !sbind.out.In)
+			if err != nil {
+				binds = append(binds, sbind)
+				freeBinds(binds)
+				return nil, fmt.Errorf("collection for column %v - error: %v", i, err)
+			}
+
+		case JSON:
+			err = stmt.makeJSONBind(&sbind, value)
+			if err != nil {
+				binds = append(binds, sbind)
+				freeBinds(binds)
+				return nil, fmt.Errorf("JSON for column %v - error: %v", i, err)
+			}
+
+		case Vector, SparseVector:
+			err = stmt.makeVectorBind(&sbind, value)
+			if err != nil {
+				binds = append(binds, sbind)
+				freeBinds(binds)
+				return nil, fmt.Errorf("vector for column %v - error: %v", i, err)
+			}
+
+		case XMLType:
+			err = stmt.makeXMLTypeBind(&sbind, value)
+			if err != nil {
+				binds = append(binds, sbind)
+				freeBinds(binds)
+				return nil, fmt.Errorf("XMLType for column %v - error: %v", i, err)
+			}
+
+		case *driver.Rows:
+			// ref cursor, the statement handle is set by the execute
+			var stmtP *unsafe.Pointer
+			stmtP, _, err = stmt.conn.ociHandleAlloc(C.OCI_HTYPE_STMT, 0)
+			if err != nil {
+				binds = append(binds, sbind)
+				freeBinds(binds)
+				return nil, fmt.Errorf("allocate ref cursor handle for column %v - error: %v", i, err)
+			}
+			sbind.dataType = C.SQLT_RSET
+			sbind.pbuf = unsafe.Pointer(stmtP)
+			sbind.maxSize = 0
+
 		case nil:
 			sbind.dataType = C.SQLT_AFC
 			sbind.pbuf = nil
@@ -304,7 +537,26 @@ func (stmt *Stmt) bindValues(values []driver.Value, namedValues []driver.NamedVa
 				*sbind.indicator = -1 // set to null
 			}
 
-		case bool: // oracle does not have bool, handle as 0/1 int
+		case Boolean:
+			if clientVersionMajor < 12 {
+				binds = append(binds, sbind)
+				freeBinds(binds)
+				return nil, fmt.Errorf("boolean for column %v - error: native BOOLEAN needs Oracle Client 12.1 or later", i)
+			}
+			sbind.dataType = C.SQLT_BOL
+			sbind.pbuf = C.malloc(C.sizeof_boolean)
+			*(*C.boolean)(sbind.pbuf) = C.FALSE
+			if value.Bool {
+				*(*C.boolean)(sbind.pbuf) = C.TRUE
+			}
+			sbind.maxSize = C.sizeof_boolean
+			*sbind.length = C.sizeof_boolean
+			if !value.Valid {
+				*sbind.indicator = -1 // set to null
+			}
+
+		case bool:
+			// bool is bound as a 0/1 int, Boolean binds a native BOOLEAN
 			sbind.dataType = C.SQLT_INT
 			if value {
 				sbind.pbuf = unsafe.Pointer(cByte([]byte{1}))
@@ -317,6 +569,33 @@ func (stmt *Stmt) bindValues(values []driver.Value, namedValues []driver.NamedVa
 				*sbind.indicator = -1 // set to null
 			}
 
+		case json.Number, *big.Int, *big.Rat: // exact NUMBER
+			var number string
+			number, err = numberToDecimalString(value)
+			if err != nil {
+				binds = append(binds, sbind)
+				freeBinds(binds)
+				return nil, fmt.Errorf("number for column %v - error: %v", i, err)
+			}
+			if number == "" {
+				sbind.dataType = C.SQLT_NUM
+				sbind.pbuf = nil
+				sbind.maxSize = 0
+				*sbind.indicator = -1 // set to null
+				break
+			}
+			var buffer []byte
+			buffer, err = encodeNumber(number)
+			if err != nil {
+				binds = append(binds, sbind)
+				freeBinds(binds)
+				return nil, fmt.Errorf("number for column %v - error: %v", i, err)
+			}
+			sbind.dataType = C.SQLT_NUM
+			sbind.pbuf = unsafe.Pointer(cByte(buffer))
+			sbind.maxSize = C.sb4(len(buffer))
+			*sbind.length = C.ub2(len(buffer))
+
 		default:
 			if isOut {
 				// TODO: should this error instead of setting to null?
@@ -325,6 +604,20 @@ func (stmt *Stmt) bindValues(values []driver.Value, namedValues []driver.NamedVa
 				sbind.maxSize = 0
 				*sbind.length = 0
 				*sbind.indicator = -1 // set to null
+			} else if isArrayBind(value) && stmt.plsqlArrays {
+				err = stmt.makePLSQLArrayBind(&sbind, reflect.ValueOf(value), false, true)
+				if err != nil {
+					binds = append(binds, sbind)
+					freeBinds(binds)
+					return nil, fmt.Errorf("PL/SQL array bind for column %v - error: %v", i, err)
+				}
+			} else if isArrayBind(value) {
+				err = stmt.makeArrayBind(&sbind, reflect.ValueOf(value))
+				if err != nil {
+					binds = append(binds, sbind)
+					freeBinds(binds)
+					return nil, fmt.Errorf("array bind for column %v - error: %v", i, err)
+				}
 			} else {
 				d := fmt.Sprintf("%v", value)
 				sbind.dataType = C.SQLT_AFC
@@ -337,12 +630,21 @@ func (stmt *Stmt) bindValues(values []driver.Value, namedValues []driver.NamedVa
 		// add to binds now so if error will be freed by freeBinds call
 		binds = append(binds, sbind)
 
-		if useValues || len(namedValues[i].Name) < 1 {
+		if stmt.ordinalBinds > 0 && (useValues || len(namedValues[i].Name) < 1) {
+			// $N placeholders are rewritten to :N, which binds every use of the argument
+			err = stmt.ociBindByName([]byte(":"+strconv.Itoa(i+1)), &sbind)
+		} else if useValues || len(namedValues[i].Name) < 1 {
 			err = stmt.ociBindByPos(C.ub4(i+1), &sbind)
 			// TODO: should we use namedValues[i]Ordinal?
 		} else {
 			err = stmt.ociBindByName([]byte(":"+namedValues[i].Name), &sbind)
 		}
+		if err == nil && sbind.returning != nil {
+			err = stmt.ociBindDynamic(&sbind)
+		}
+		if err == nil && sbind.object != nil {
+			err = stmt.ociBindObject(&sbind)
+		}
 		if err != nil {
 			freeBinds(binds)
 			return nil, err
@@ -353,9 +655,160 @@ func (stmt *Stmt) bindValues(values []driver.Value, namedValues []driver.NamedVa
 	return binds, nil
 }
 
+// makeArrayBind lays out the elements of a Go slice as an OCI bind array,
+// with an indicator and length for each element
+func (stmt *Stmt) makeArrayBind(sbind *bindStruct, rv reflect.Value) error {
+	count := rv.Len()
+
+	// convert elements to driver values, the first non nil element decides the bind type
+	values := make([]driver.Value, count)
+	var valueType reflect.Type
+	var err error
+	for i := 0; i < count; i++ {
+		values[i], err = driver.DefaultParameterConverter.ConvertValue(rv.Index(i).Interface())
+		if err != nil {
+			return fmt.Errorf("element %v - error: %v", i, err)
+		}
+		if values[i] == nil {
+			continue
+		}
+		if valueType == nil {
+			valueType = reflect.TypeOf(values[i])
+		} else if valueType != reflect.TypeOf(values[i]) {
+			return fmt.Errorf("element %v is type %T, expected %v", i, values[i], valueType)
+		}
+	}
+
+	var maxSize int
+	switch valueType {
+	case nil, typeBool:
+		maxSize = 1
+	case typeInt64, typeFloat64:
+		maxSize = 8
+	case typeTime:
+		maxSize = int(sizeOfNilPointer)
+	case typeString, typeSliceByte:
+		for i := 0; i < count; i++ {
+			var size int
+			switch value := values[i].(type) {
+			case string:
+				size = len(value)
+			case []byte:
+				size = len(value)
+			}
+			if size > 32767 {
+				return fmt.Errorf("element %v length %v is greater than max array bind length 32767", i, size)
+			}
+			if size > maxSize {
+				maxSize = size
+			}
+		}
+		if maxSize < 1 {
+			maxSize = 1
+		}
+	default:
+		return fmt.Errorf("unsupported array element type %v", valueType)
+	}
+
+	// replace the scalar length and indicator with arrays
+	C.free(unsafe.Pointer(sbind.length))
+	C.free(unsafe.Pointer(sbind.indicator))
+	sbind.isArray = true
+	sbind.arrayLength = count
+	sbind.maxSize = C.sb4(maxSize)
+	allocCount := count
+	if allocCount < 1 {
+		allocCount = 1
+	}
+	sbind.length = (*C.ub2)(C.malloc(C.size_t(allocCount) * C.sizeof_ub2))
+	sbind.indicator = (*C.sb2)(C.malloc(C.size_t(allocCount) * C.sizeof_sb2))
+	sbind.pbuf = C.calloc(C.size_t(allocCount), C.size_t(maxSize))
+	lengths := (*[1 << 30]C.ub2)(unsafe.Pointer(sbind.length))[:allocCount:allocCount]
+	indicators := (*[1 << 30]C.sb2)(unsafe.Pointer(sbind.indicator))[:allocCount:allocCount]
+	buffer := (*[1 << 30]byte)(sbind.pbuf)[: allocCount*maxSize : allocCount*maxSize]
+
+	switch valueType {
+	case nil, typeString:
+		sbind.dataType = C.SQLT_AFC
+	case typeSliceByte:
+		sbind.dataType = C.SQLT_BIN
+	case typeBool, typeInt64:
+		sbind.dataType = C.SQLT_INT
+	case typeFloat64:
+		sbind.dataType = C.SQLT_BDOUBLE
+	case typeTime:
+		sbind.dataType = C.SQLT_TIMESTAMP_TZ
+	}
+
+	for i := 0; i < count; i++ {
+		element := buffer[i*maxSize : (i+1)*maxSize]
+		indicators[i] = 0
+		lengths[i] = C.ub2(maxSize)
+
+		if valueType == typeTime {
+			// every element needs a descriptor, even if null
+			aTime, _ := values[i].(time.Time)
+			var dateTimePP *unsafe.Pointer
+			dateTimePP, err = stmt.conn.timeToOCIDateTime(&aTime)
+			if err != nil {
+				return fmt.Errorf("timeToOCIDateTime for element %v - error: %v", i, err)
+			}
+			*(*unsafe.Pointer)(unsafe.Pointer(&element[0])) = *dateTimePP
+		}
+
+		switch value := values[i].(type) {
+		case nil:
+			indicators[i] = -1 // set to null
+			lengths[i] = 0
+		case bool:
+			if value {
+				element[0] = 1
+			}
+		case int64:
+			binary.LittleEndian.PutUint64(element, uint64(value))
+		case float64:
+			binary.LittleEndian.PutUint64(element, math.Float64bits(value))
+		case string:
+			lengths[i] = C.ub2(copy(element, value))
+		case []byte:
+			lengths[i] = C.ub2(copy(element, value))
+		}
+	}
+
+	return nil
+}
+
+// arrayBindIters returns the number of iterations needed to execute the statement with the binds.
+// Array binds must all be the same length and can not be mixed with scalar binds, other than returning binds.
+func arrayBindIters(binds []bindStruct) (int, error) {
+	iters := -1
+	for i := 0; i < len(binds); i++ {
+		if !binds[i].isArray {
+			continue
+		}
+		if iters == -1 {
+			iters = binds[i].arrayLength
+		} else if iters != binds[i].arrayLength {
+			return 0, fmt.Errorf("array bind for column %v has length %v, expected %v", i, binds[i].arrayLength, iters)
+		}
+	}
+	if iters == -1 {
+		return 1, nil
+	}
+
+	for i := 0; i < len(binds); i++ {
+		if !binds[i].isArray && binds[i].returning == nil {
+			return 0, fmt.Errorf("column %v is not an array, array binds can not be mixed with scalar binds", i)
+		}
+	}
+
+	return iters, nil
+}
+
 // Query runs a query
 func (stmt *Stmt) Query(values []driver.Value) (driver.Rows, error) {
 	stmt.ctx = context.Background()
+	defer stmt.resetOptions()
 	binds, err := stmt.bindValues(values, nil)
 	if err != nil {
 		return nil, err
@@ -367,6 +820,7 @@ func (stmt *Stmt) Query(values []driver.Value) (driver.Rows, error) {
 // QueryContext runs a query with context
 func (stmt *Stmt) QueryContext(ctx context.Context, namedValues []driver.NamedValue) (driver.Rows, error) {
 	stmt.ctx = ctx
+	defer stmt.resetOptions()
 	binds, err := stmt.bindValues(nil, namedValues)
 	if err != nil {
 		return nil, err
@@ -379,6 +833,12 @@ func (stmt *Stmt) QueryContext(ctx context.Context, namedValues []driver.NamedVa
 func (stmt *Stmt) query(binds []bindStruct) (driver.Rows, error) {
 	defer freeBinds(binds)
 
+	for i := 0; i < len(binds); i++ {
+		if binds[i].isArray {
+			return nil, fmt.Errorf("array bind for column %v is not supported for queries", i)
+		}
+	}
+
 	var stmtType C.ub2
 	_, err := stmt.ociAttrGet(unsafe.Pointer(&stmtType), C.OCI_ATTR_STMT_TYPE)
 	if err != nil {
@@ -418,16 +878,34 @@ func (stmt *Stmt) query(binds []bindStruct) (driver.Rows, error) {
 		return nil, stmt.ctx.Err()
 	}
 
-	done := make(chan struct{})
-	go stmt.conn.ociBreakDone(stmt.ctx, done)
+	stmt.conn.callBegin(stmt.ctx)
 	err = stmt.ociStmtExecute(iter, mode)
-	close(done)
+	stmt.conn.callEnd()
 	if err != nil {
 		return nil, err
 	}
 
+	if stmtType == C.OCI_STMT_BEGIN || stmtType == C.OCI_STMT_DECLARE || stmtType == C.OCI_STMT_CALL {
+		// PL/SQL can return implicit result sets with DBMS_SQL.RETURN_RESULT
+		var implicitResultCount C.ub4
+		_, err = stmt.ociAttrGet(unsafe.Pointer(&implicitResultCount), C.OCI_ATTR_IMPLICIT_RESULT_COUNT)
+		// an error means implicit results are not supported by the client or server, so there are none
+		if err == nil && implicitResultCount > 0 {
+			rows := &Rows{
+				implicitStmt:    stmt,
+				implicitResults: int(implicitResultCount),
+				numberType:      stmt.numberType,
+			}
+			err = rows.nextImplicitResult()
+			if err != nil {
+				return nil, err
+			}
+			return rows, nil
+		}
+	}
+
 	var defines []defineStruct
-	defines, err = stmt.makeDefines()
+	defines, err = stmt.makeDefines(int(stmt.conn.fetchArraySize))
 	if err != nil {
 		return nil, err
 	}
@@ -438,14 +916,19 @@ func (stmt *Stmt) query(binds []bindStruct) (driver.Rows, error) {
 	}
 
 	rows := &Rows{
-		stmt:    stmt,
-		defines: defines,
+		stmt:       stmt,
+		defines:    defines,
+		numberType: stmt.numberType,
+	}
+	if len(defines) > 0 {
+		rows.fetchArraySize = defines[0].arraySize
 	}
 
 	return rows, nil
 }
 
-func (stmt *Stmt) makeDefines() ([]defineStruct, error) {
+// makeDefines makes the defines for the select-list, with buffers that hold fetchArraySize rows
+func (stmt *Stmt) makeDefines(fetchArraySize int) ([]defineStruct, error) {
 	var paramCountUb4 C.ub4 // number of columns in the select-list
 	_, err := stmt.ociAttrGet(unsafe.Pointer(&paramCountUb4), C.OCI_ATTR_PARAM_COUNT)
 	if err != nil {
@@ -455,7 +938,36 @@ func (stmt *Stmt) makeDefines() ([]defineStruct, error) {
 
 	defines := make([]defineStruct, paramCount)
 
+	if fetchArraySize < 1 {
+		fetchArraySize = 1
+	}
+
+	// ref cursors are fetched one row at a time because the sub defines are made for the cursor of the current row.
+	// Objects are fetched one row at a time because each row needs an object instance.
+	for i := 0; i < paramCount; i++ {
+		var param *C.OCIParam
+		param, err = stmt.ociParamGet(C.ub4(i + 1))
+		if err != nil {
+			return nil, err
+		}
+		var dataType C.ub2
+		_, err = stmt.conn.ociAttrGet(param, unsafe.Pointer(&dataType), C.OCI_ATTR_DATA_TYPE)
+		isXMLType := false
+		if err == nil && dataType == C.SQLT_NTY {
+			isXMLType, err = stmt.conn.isXMLType(param)
+		}
+		C.OCIDescriptorFree(unsafe.Pointer(param), C.OCI_DTYPE_PARAM)
+		if err != nil {
+			return nil, err
+		}
+		if dataType == C.SQLT_RSET || (dataType == C.SQLT_NTY && !isXMLType) {
+			fetchArraySize = 1
+			break
+		}
+	}
+
 	for i := 0; i < paramCount; i++ {
+		defines[i].arraySize = fetchArraySize
 		if stmt.ctx.Err() != nil {
 			freeDefines(defines)
 			return nil, stmt.ctx.Err()
@@ -492,10 +1004,8 @@ func (stmt *Stmt) makeDefines() ([]defineStruct, error) {
 			return nil, err
 		}
 
-		defines[i].length = (*C.ub2)(C.malloc(C.sizeof_ub2))
-		*defines[i].length = 0
-		defines[i].indicator = (*C.sb2)(C.malloc(C.sizeof_sb2))
-		*defines[i].indicator = 0
+		defines[i].length = (*C.ub2)(C.calloc(C.size_t(fetchArraySize), C.sizeof_ub2))
+		defines[i].indicator = (*C.sb2)(C.calloc(C.size_t(fetchArraySize), C.sizeof_sb2))
 
 		// switch on dataType
 		switch dataType {
@@ -505,12 +1015,12 @@ func (stmt *Stmt) makeDefines() ([]defineStruct, error) {
 			// For a database with character set to ZHS16GBK the OCI C driver does not seem to report the correct max size, not sure exactly why.
 			// Doubling the max size of the buffer seems to fix the issue, not sure if there is a better fix.
 			defines[i].maxSize = C.sb4(maxSize * 2)
-			defines[i].pbuf = C.malloc(C.size_t(defines[i].maxSize))
+			defines[i].pbuf = C.malloc(C.size_t(defines[i].maxSize) * C.size_t(fetchArraySize))
 
 		case C.SQLT_BIN:
 			defines[i].dataType = C.SQLT_BIN
 			defines[i].maxSize = C.sb4(maxSize)
-			defines[i].pbuf = C.malloc(C.size_t(defines[i].maxSize))
+			defines[i].pbuf = C.malloc(C.size_t(defines[i].maxSize) * C.size_t(fetchArraySize))
 
 		case C.SQLT_NUM:
 			var precision C.sb2 // the precision
@@ -534,8 +1044,29 @@ func (stmt *Stmt) makeDefines() ([]defineStruct, error) {
 
 			// note that select sum and count both return as precision == 0 && scale == 0 so use float64 (SQLT_BDOUBLE) to handle both
 
+			aNumberType := stmt.conn.numberType
+			if stmt.numberType > 0 {
+				// checked by CheckNamedValue
+				aNumberType, _ = stmt.numberType.numberType()
+			}
+			if aNumberType != numberTypeFloat {
+				// exact NUMBER, decoded in Go
+				defines[i].dataType = C.SQLT_NUM
+				defines[i].maxSize = numberMaxLength
+				defines[i].pbuf = C.malloc(C.size_t(defines[i].maxSize) * C.size_t(fetchArraySize))
+				defines[i].numberType = aNumberType
+				if defines[i].numberType == numberTypeBig {
+					if precision > 0 && scale == 0 {
+						defines[i].numberType = numberTypeBigInt
+					} else {
+						defines[i].numberType = numberTypeBigRat
+					}
+				}
+				break
+			}
+
 			defines[i].maxSize = 8
-			defines[i].pbuf = C.malloc(C.size_t(defines[i].maxSize))
+			defines[i].pbuf = C.malloc(C.size_t(defines[i].maxSize) * C.size_t(fetchArraySize))
 
 			switch {
 			case precision != 0 && scale == -127:
@@ -551,77 +1082,103 @@ func (stmt *Stmt) makeDefines() ([]defineStruct, error) {
 		case C.SQLT_INT:
 			defines[i].dataType = C.SQLT_INT
 			defines[i].maxSize = 8
-			defines[i].pbuf = C.malloc(C.size_t(defines[i].maxSize))
+			defines[i].pbuf = C.malloc(C.size_t(defines[i].maxSize) * C.size_t(fetchArraySize))
 
 		case C.SQLT_BDOUBLE, C.SQLT_IBDOUBLE, C.SQLT_BFLOAT, C.SQLT_IBFLOAT:
 			defines[i].dataType = C.SQLT_BDOUBLE
 			defines[i].maxSize = 8
-			defines[i].pbuf = C.malloc(C.size_t(defines[i].maxSize))
+			defines[i].pbuf = C.malloc(C.size_t(defines[i].maxSize) * C.size_t(fetchArraySize))
+
+		case C.SQLT_BOL: // Oracle 23 BOOLEAN
+			defines[i].dataType = C.SQLT_BOL
+			defines[i].maxSize = C.sizeof_boolean
+			defines[i].pbuf = C.malloc(C.size_t(defines[i].maxSize) * C.size_t(fetchArraySize))
 
 		case C.SQLT_LNG:
 			defines[i].dataType = C.SQLT_LNG
 			defines[i].maxSize = 4000
-			defines[i].pbuf = C.malloc(C.size_t(defines[i].maxSize))
+			defines[i].pbuf = C.malloc(C.size_t(defines[i].maxSize) * C.size_t(fetchArraySize))
 
 		case C.SQLT_CLOB, C.SQLT_BLOB:
 			defines[i].dataType = dataType
 			defines[i].maxSize = C.sb4(sizeOfNilPointer)
-			var lobP *unsafe.Pointer
-			lobP, _, err = stmt.conn.ociDescriptorAlloc(C.OCI_DTYPE_LOB, 0)
+			defines[i].pbuf, err = stmt.conn.ociDescriptorAllocArray(C.OCI_DTYPE_LOB, fetchArraySize)
 			if err != nil {
 				freeDefines(defines)
 				return nil, err
 			}
-			defines[i].pbuf = unsafe.Pointer(lobP)
 
 		case C.SQLT_TIMESTAMP, C.SQLT_DAT:
 			defines[i].dataType = C.SQLT_TIMESTAMP
 			defines[i].maxSize = C.sb4(sizeOfNilPointer)
-			var timestampP *unsafe.Pointer
-			timestampP, _, err = stmt.conn.ociDescriptorAlloc(C.OCI_DTYPE_TIMESTAMP, 0)
+			defines[i].pbuf, err = stmt.conn.ociDescriptorAllocArray(C.OCI_DTYPE_TIMESTAMP, fetchArraySize)
 			if err != nil {
 				freeDefines(defines)
 				return nil, err
 			}
-			defines[i].pbuf = unsafe.Pointer(timestampP)
 
 		case C.SQLT_TIMESTAMP_TZ, C.SQLT_TIMESTAMP_LTZ:
 			defines[i].dataType = C.SQLT_TIMESTAMP_TZ
 			defines[i].maxSize = C.sb4(sizeOfNilPointer)
-			var timestampP *unsafe.Pointer
-			timestampP, _, err = stmt.conn.ociDescriptorAlloc(C.OCI_DTYPE_TIMESTAMP_TZ, 0)
+			defines[i].pbuf, err = stmt.conn.ociDescriptorAllocArray(C.OCI_DTYPE_TIMESTAMP_TZ, fetchArraySize)
 			if err != nil {
 				freeDefines(defines)
 				return nil, err
 			}
-			defines[i].pbuf = unsafe.Pointer(timestampP)
 
 		case C.SQLT_INTERVAL_DS:
 			defines[i].dataType = C.SQLT_INTERVAL_DS
 			defines[i].maxSize = C.sb4(sizeOfNilPointer)
-			var intervalP *unsafe.Pointer
-			intervalP, _, err = stmt.conn.ociDescriptorAlloc(C.OCI_DTYPE_INTERVAL_DS, 0)
+			defines[i].pbuf, err = stmt.conn.ociDescriptorAllocArray(C.OCI_DTYPE_INTERVAL_DS, fetchArraySize)
 			if err != nil {
 				freeDefines(defines)
 				return nil, err
 			}
-			defines[i].pbuf = unsafe.Pointer(intervalP)
 
 		case C.SQLT_INTERVAL_YM:
 			defines[i].dataType = C.SQLT_INTERVAL_YM
 			defines[i].maxSize = C.sb4(sizeOfNilPointer)
-			var intervalP *unsafe.Pointer
-			intervalP, _, err = stmt.conn.ociDescriptorAlloc(C.OCI_DTYPE_INTERVAL_YM, 0)
+			defines[i].pbuf, err = stmt.conn.ociDescriptorAllocArray(C.OCI_DTYPE_INTERVAL_YM, fetchArraySize)
+			if err != nil {
+				freeDefines(defines)
+				return nil, err
+			}
+
+		case C.SQLT_JSON: // Oracle 21 JSON
+			defines[i].dataType = C.SQLT_JSON
+			defines[i].maxSize = C.sb4(sizeOfNilPointer)
+			defines[i].pbuf, err = stmt.conn.ociDescriptorAllocArray(C.OCI_DTYPE_JSON, fetchArraySize)
+			if err != nil {
+				freeDefines(defines)
+				return nil, err
+			}
+
+		case C.SQLT_VEC: // Oracle 23 VECTOR
+			// the format is 0 for a flexible format column
+			_, err = stmt.conn.ociAttrGet(param, unsafe.Pointer(&defines[i].vectorFormat), C.OCI_ATTR_VECTOR_DATA_FORMAT)
+			if err != nil {
+				freeDefines(defines)
+				return nil, err
+			}
+			var property C.ub1
+			_, err = stmt.conn.ociAttrGet(param, unsafe.Pointer(&property), C.OCI_ATTR_VECTOR_PROPERTY)
+			if err != nil {
+				freeDefines(defines)
+				return nil, err
+			}
+			defines[i].vectorSparse = property&C.OCI_ATTR_VECTOR_PROPERTY_SPARSE != 0
+			defines[i].dataType = C.SQLT_VEC
+			defines[i].maxSize = C.sb4(sizeOfNilPointer)
+			defines[i].pbuf, err = stmt.conn.ociDescriptorAllocArray(C.OCI_DTYPE_VECTOR, fetchArraySize)
 			if err != nil {
 				freeDefines(defines)
 				return nil, err
 			}
-			defines[i].pbuf = unsafe.Pointer(intervalP)
 
 		case C.SQLT_RDD: // rowid
 			defines[i].dataType = C.SQLT_AFC
 			defines[i].maxSize = 40
-			defines[i].pbuf = C.malloc(C.size_t(defines[i].maxSize))
+			defines[i].pbuf = C.malloc(C.size_t(defines[i].maxSize) * C.size_t(fetchArraySize))
 
 		case C.SQLT_RSET: // ref cursor
 			defines[i].dataType = dataType
@@ -632,12 +1189,53 @@ func (stmt *Stmt) makeDefines() ([]defineStruct, error) {
 				freeDefines(defines)
 				return nil, err
 			}
-			defines[i].pbuf = unsafe.Pointer(stmtP)
+			defines[i].pbuf = C.malloc(C.size_t(sizeOfNilPointer))
+			*(*unsafe.Pointer)(defines[i].pbuf) = *stmtP
+
+		case C.SQLT_NTY: // object or collection, the instance is set by OCIDefineObject
+			var isXMLType bool
+			isXMLType, err = stmt.conn.isXMLType(param)
+			if err != nil {
+				freeDefines(defines)
+				return nil, err
+			}
+			if isXMLType {
+				// XMLType is converted to a CLOB so large documents are not limited to the VARCHAR2 size
+				defines[i].dataType = C.SQLT_CLOB
+				defines[i].maxSize = C.sb4(sizeOfNilPointer)
+				defines[i].pbuf, err = stmt.conn.ociDescriptorAllocArray(C.OCI_DTYPE_LOB, fetchArraySize)
+				if err != nil {
+					freeDefines(defines)
+					return nil, err
+				}
+				break
+			}
+
+			var schemaName, typeName string
+			schemaName, err = stmt.conn.paramString(param, C.OCI_ATTR_SCHEMA_NAME)
+			if err != nil {
+				freeDefines(defines)
+				return nil, err
+			}
+			typeName, err = stmt.conn.paramString(param, C.OCI_ATTR_TYPE_NAME)
+			if err != nil {
+				freeDefines(defines)
+				return nil, err
+			}
+			var objType *objectType
+			objType, err = stmt.conn.objectTypeByName(schemaName + "." + typeName)
+			if err != nil {
+				freeDefines(defines)
+				return nil, err
+			}
+			defines[i].dataType = C.SQLT_NTY
+			defines[i].maxSize = 0
+			defines[i].object = newObjectInstance(objType)
 
 		default:
 			defines[i].dataType = C.SQLT_AFC
 			defines[i].maxSize = C.sb4(maxSize)
-			defines[i].pbuf = C.malloc(C.size_t(defines[i].maxSize))
+			defines[i].pbuf = C.malloc(C.size_t(defines[i].maxSize) * C.size_t(fetchArraySize))
 		}
 
 		result := C.OCIDefineByPos(
@@ -657,6 +1255,14 @@ func (stmt *Stmt) makeDefines() ([]defineStruct, error) {
 			freeDefines(defines)
 			return nil, stmt.conn.getError(result)
 		}
+
+		if defines[i].object != nil {
+			err = stmt.ociDefineObject(&defines[i])
+			if err != nil {
+				freeDefines(defines)
+				return nil, err
+			}
+		}
 	}
 
 	return defines, nil
@@ -700,6 +1306,7 @@ func (stmt *Stmt) rowsAffected() (int64, error) {
 // Exec runs an exec query
 func (stmt *Stmt) Exec(values []driver.Value) (driver.Result, error) {
 	stmt.ctx = context.Background()
+	defer stmt.resetOptions()
 	binds, err := stmt.bindValues(values, nil)
 	if err != nil {
 		return nil, err
@@ -711,6 +1318,7 @@ func (stmt *Stmt) Exec(values []driver.Value) (driver.Result, error) {
 // ExecContext run a exec query with context
 func (stmt *Stmt) ExecContext(ctx context.Context, namedValues []driver.NamedValue) (driver.Result, error) {
 	stmt.ctx = ctx
+	defer stmt.resetOptions()
 	binds, err := stmt.bindValues(nil, namedValues)
 	if err != nil {
 		return nil, err
@@ -722,27 +1330,89 @@ func (stmt *Stmt) ExecContext(ctx context.Context, namedValues []driver.NamedVal
 func (stmt *Stmt) exec(binds []bindStruct) (driver.Result, error) {
 	defer freeBinds(binds)
 
+	iters, err := arrayBindIters(binds)
+	if err != nil {
+		return nil, err
+	}
+	if iters < 1 {
+		// empty array binds, nothing to execute
+		if stmt.dmlRowCounts != nil {
+			*stmt.dmlRowCounts = DMLRowCounts{}
+		}
+		return &Result{stmt: stmt, rowidErr: ErrNoRowid}, nil
+	}
+
+	var isArrayDML bool
+	for i := 0; i < len(binds); i++ {
+		isArrayDML = isArrayDML || binds[i].isArray
+	}
+	batchErrors := stmt.batchErrors && isArrayDML
+
 	mode := C.ub4(C.OCI_DEFAULT)
-	if stmt.conn.inTransaction == false {
+	if stmt.conn.inTransaction == false && !batchErrors {
 		mode = mode | C.OCI_COMMIT_ON_SUCCESS
 	}
+	if batchErrors {
+		mode = mode | C.OCI_BATCH_ERRORS
+	}
+	if stmt.dmlRowCounts != nil {
+		mode = mode | C.OCI_RETURN_ROW_COUNT_ARRAY
+	}
 
 	if stmt.ctx.Err() != nil {
 		return nil, stmt.ctx.Err()
 	}
 
-	done := make(chan struct{})
-	go stmt.conn.ociBreakDone(stmt.ctx, done)
-	err := stmt.ociStmtExecute(1, mode)
-	close(done)
+	stmt.conn.callBegin(stmt.ctx)
+	err = stmt.ociStmtExecute(C.ub4(iters), mode)
+	stmt.conn.callEnd()
+
+	var rowErrors []BatchRowError
+	if batchErrors {
+		// with batch errors the execute returns an error if any rows failed, the rows without errors have been processed
+		var batchErr error
+		rowErrors, batchErr = stmt.getBatchErrors()
+		if batchErr != nil {
+			stmt.rollbackBatchErrors()
+			return nil, batchErr
+		}
+		if len(rowErrors) > 0 {
+			err = nil
+		}
+	}
+
 	if err != nil && err != ErrOCISuccessWithInfo {
+		if batchErrors {
+			stmt.rollbackBatchErrors()
+		}
 		return nil, err
 	}
 
+	if batchErrors && stmt.conn.inTransaction == false {
+		// OCI_COMMIT_ON_SUCCESS is not used with batch errors, so commit the rows that were processed
+		if rv := C.OCITransCommit(
+			stmt.conn.svc,
+			stmt.conn.errHandle,
+			0,
+		); rv != C.OCI_SUCCESS {
+			err = stmt.conn.getError(rv)
+			stmt.rollbackBatchErrors()
+			return nil, err
+		}
+	}
+
+	if stmt.dmlRowCounts != nil {
+		*stmt.dmlRowCounts, err = stmt.getDMLRowCounts()
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	result := Result{stmt: stmt}
 
+	// for array binds, OCI_ATTR_ROW_COUNT is the total for all iterations
 	result.rowsAffected, result.rowsAffectedErr = stmt.rowsAffected()
-	if result.rowsAffectedErr != nil || result.rowsAffected < 1 {
+	if result.rowsAffectedErr != nil || result.rowsAffected < 1 || iters > 1 {
 		result.rowidErr = ErrNoRowid
 	} else {
 		result.rowid, result.rowidErr = stmt.getRowid()
@@ -753,17 +1423,214 @@ func (stmt *Stmt) exec(binds []bindStruct) (driver.Result, error) {
 		return nil, err
 	}
 
+	if len(rowErrors) > 0 {
+		return nil, &BatchError{RowsAffected: result.rowsAffected, Errors: rowErrors}
+	}
+
 	return &result, nil
 }
 
-// outputBoundParameters sets bound parameters
-func (stmt *Stmt) outputBoundParameters(binds []bindStruct) error {
-	var err error
+// resetOptions resets the array DML options and the number type set by CheckNamedValue.
+// The options are only for one execution, so Exec and Query defer the reset before binding.
+func (stmt *Stmt) resetOptions() {
+	stmt.batchErrors = false
+	stmt.dmlRowCounts = nil
+	stmt.plsqlArrays = false
+	stmt.numberType = 0
+}
+
+// rollbackBatchErrors rolls back the rows processed by a batch errors array DML that returns an error
+// when not in a transaction, so they are not committed by the next statement
+func (stmt *Stmt) rollbackBatchErrors() {
+	if stmt.conn.inTransaction {
+		return
+	}
+	if rv := C.OCITransRollback(
+		stmt.conn.svc,
+		stmt.conn.errHandle,
+		0,
+	); rv != C.OCI_SUCCESS {
+		stmt.conn.logger.Print("batch errors rollback error: ", stmt.conn.getError(rv))
+		stmt.conn.badConn = true
+	}
+}
+
+// getBatchErrors returns the row errors of an array DML executed with OCI_BATCH_ERRORS
+func (stmt *Stmt) getBatchErrors() ([]BatchRowError, error) {
+	var numErrors C.ub4 // the number of errors in the array DML
+	_, err := stmt.ociAttrGet(unsafe.Pointer(&numErrors), C.OCI_ATTR_NUM_DML_ERRORS)
+	if err != nil {
+		return nil, err
+	}
+	if numErrors < 1 {
+		return nil, nil
+	}
+
+	// error handle reused for each row error record
+	handle, _, err := stmt.conn.ociHandleAlloc(C.OCI_HTYPE_ERROR, 0)
+	if err != nil {
+		return nil, fmt.Errorf("allocate error handle error: %v", err)
+	}
+	defer C.OCIHandleFree(*handle, C.OCI_HTYPE_ERROR)
+
+	rowErrors := make([]BatchRowError, int(numErrors))
+	for i := 0; i < len(rowErrors); i++ {
+		result := C.OCIParamGet(
+			unsafe.Pointer(stmt.conn.errHandle), // the error handle of the execute
+			C.OCI_HTYPE_ERROR,                   // handle type: OCI_HTYPE_ERROR, for the row error records
+			stmt.conn.errHandle,                 // an error handle
+			handle,                              // error handle for the row error record
+			C.ub4(i),                            // row error record, starts from 0
+		)
+		err = stmt.conn.getError(result)
+		if err != nil {
+			return nil, err
+		}
+
+		var rowOffset C.ub4 // the row offset into the bind arrays
+		result = C.OCIAttrGet(
+			*handle,                    // error handle for the row error record
+			C.OCI_HTYPE_ERROR,          // handle type
+			unsafe.Pointer(&rowOffset), // row offset
+			nil,                        // size of the attribute value
+			C.OCI_ATTR_DML_ROW_OFFSET,  // attribute type
+			stmt.conn.errHandle,        // an error handle
+		)
+		err = stmt.conn.getError(result)
+		if err != nil {
+			return nil, err
+		}
+
+		rowErrors[i].Offset = int(rowOffset)
+		var rowErr error
+		rowErrors[i].Code, rowErr = ociGetErrorFromHandle((*C.OCIError)(*handle))
+		rowErrors[i].Message = rowErr.Error()
+	}
+
+	return rowErrors, nil
+}
+
+// getDMLRowCounts returns the number of rows affected by each iteration of an array DML executed with OCI_RETURN_ROW_COUNT_ARRAY
+func (stmt *Stmt) getDMLRowCounts() (DMLRowCounts, error) {
+	var rowCountsP *C.ub8 // array of row counts, one for each iteration
+	size, err := stmt.ociAttrGet(unsafe.Pointer(&rowCountsP), C.OCI_ATTR_DML_ROW_COUNT_ARRAY)
+	if err != nil {
+		return nil, err
+	}
+
+	count := int(size)
+	rowCounts := make(DMLRowCounts, count)
+	if count < 1 || rowCountsP == nil {
+		return rowCounts, nil
+	}
+	counts := (*[1 << 27]C.ub8)(unsafe.Pointer(rowCountsP))[:count:count]
+	for i := 0; i < count; i++ {
+		rowCounts[i] = int64(counts[i])
+	}
+
+	return rowCounts, nil
+}
+
+// Error returns the batch error string
+func (batchError *BatchError) Error() string {
+	if len(batchError.Errors) < 1 {
+		return "array DML batch error"
+	}
+	return fmt.Sprintf("array DML had %v row errors, first error %v", len(batchError.Errors), batchError.Errors[0].Error())
+}
+
+// Error returns the row error string
+func (rowError BatchRowError) Error() string {
+	return fmt.Sprintf("at row offset %v: %v", rowError.Offset, rowError.Message)
+}
+
+// Is returns true if the row error code is classified as target, like OraError.Is
+func (rowError BatchRowError) Is(target error) bool {
+	return oraErrorCodeIs(rowError.Code, target)
+}
+
+// outputBoundParameters sets bound parameters.
+// On error, the ref cursor rows already set are closed so the statement handles are freed.
+func (stmt *Stmt) outputBoundParameters(binds []bindStruct) (err error) {
+	var cursorDests []*driver.Rows
+	defer func() {
+		if err == nil {
+			return
+		}
+		for _, dest := range cursorDests {
+			(*dest).Close()
+			*dest = nil
+		}
+	}()
 
 	for i, bind := range binds {
+		if bind.returning != nil {
+			err = stmt.outputReturningBind(&bind)
+			if err != nil {
+				return err
+			}
+			continue
+		}
+		if bind.object != nil {
+			if scanner, ok := bind.out.Dest.(sql.Scanner); ok {
+				var value interface{}
+				value, err = bind.object.value()
+				if err == nil {
+					err = scanner.Scan(value)
+				}
+				if err != nil {
+					return fmt.Errorf("object for column %v - error: %v", i, err)
+				}
+			}
+			continue
+		}
+		if bind.plsqlArrayCurrent != nil {
+			if bind.out.Dest != nil {
+				err = stmt.outputPLSQLArray(&bind)
+				if err != nil {
+					return fmt.Errorf("PL/SQL array for column %v - error: %v", i, err)
+				}
+			}
+			continue
+		}
 		if bind.pbuf != nil {
 			switch dest := bind.out.Dest.(type) {
 
+			case *driver.Rows:
+				if *bind.indicator == -1 {
+					*dest = nil
+					continue
+				}
+				var rows *Rows
+				rows, err = stmt.cursorRows(*(**C.OCIStmt)(bind.pbuf))
+				if err != nil {
+					return err
+				}
+				// the rows now own the ref cursor handle
+				binds[i].pbuf = nil
+				*dest = rows
+				cursorDests = append(cursorDests, dest)
+
+			case *JSON:
+				if *bind.indicator == -1 {
+					err = dest.Scan(nil)
+				} else {
+					var value JSON
+					value, err = stmt.conn.ociJSONValue(*(**C.OCIJson)(bind.pbuf))
+					if err == nil {
+						err = dest.Scan(value)
+					}
+				}
+				if err != nil {
+					return fmt.Errorf("JSON for column %v - error: %v", i, err)
+				}
+
+			case *Vector, *SparseVector:
+				err = stmt.outputVector(&bind)
+				if err != nil {
+					return fmt.Errorf("vector for column %v - error: %v", i, err)
+				}
+
 			case *string:
 				switch {
 				case *bind.indicator > 0: // indicator variable is the actual length before truncation
@@ -881,15 +1748,21 @@ func (stmt *Stmt) outputBoundParameters(binds []bindStruct) error {
 				}
 
 			case *bool:
-				buf := (*[1 << 30]byte)(bind.pbuf)[0:1]
-				*dest = buf[0] != 0
+				*dest = bindBool(&bind)
 			case *sql.NullBool:
 				if *bind.indicator == -1 {
 					dest.Bool = false
 					dest.Valid = false
 				} else {
-					buf := (*[1 << 30]byte)(bind.pbuf)[0:1]
-					dest.Bool = buf[0] != 0
+					dest.Bool = bindBool(&bind)
+					dest.Valid = true
+				}
+			case *Boolean:
+				if *bind.indicator == -1 {
+					dest.Bool = false
+					dest.Valid = false
+				} else {
+					dest.Bool = bindBool(&bind)
 					dest.Valid = true
 				}
 
@@ -926,6 +1799,52 @@ func (stmt *Stmt) outputBoundParameters(binds []bindStruct) error {
 	return nil
 }
 
+// Scan implements sql.Scanner, a null sets Valid to false
+func (boolean *Boolean) Scan(src interface{}) error {
+	switch value := src.(type) {
+	case nil:
+		boolean.Bool, boolean.Valid = false, false
+	case bool:
+		boolean.Bool, boolean.Valid = value, true
+	case int64:
+		boolean.Bool, boolean.Valid = value != 0, true
+	default:
+		return fmt.Errorf("can not scan %T into Boolean", src)
+	}
+	return nil
+}
+
+// bindBool returns the bool value of a SQLT_BOL or 0/1 integer bind
+func bindBool(bind *bindStruct) bool {
+	if bind.dataType == C.SQLT_BOL {
+		return *(*C.boolean)(bind.pbuf) != C.FALSE
+	}
+	return *(*byte)(bind.pbuf) != 0
+}
+
+// cursorRows returns rows for an executed ref cursor out bind.
+// Closing the rows frees the ref cursor handle.
+// The rows are used after the exec returns, so they do not use the exec context.
+func (stmt *Stmt) cursorRows(cursor *C.OCIStmt) (*Rows, error) {
+	cursorStmt := &Stmt{conn: stmt.conn, stmt: cursor, ctx: context.Background(), numberType: stmt.numberType}
+	defines, err := cursorStmt.makeDefines(int(stmt.conn.fetchArraySize))
+	if err != nil {
+		return nil, err
+	}
+
+	rows := &Rows{
+		stmt:       cursorStmt,
+		defines:    defines,
+		freeCursor: true,
+		numberType: stmt.numberType,
+	}
+	if len(defines) > 0 {
+		rows.fetchArraySize = defines[0].arraySize
+	}
+
+	return rows, nil
+}
+
 // ociParamGet calls OCIParamGet then returns OCIParam and error.
 // OCIDescriptorFree must be called on returned OCIParam.
 func (stmt *Stmt) ociParamGet(position C.ub4) (*C.OCIParam, error) {
@@ -967,6 +1886,12 @@ func (stmt *Stmt) ociAttrGet(value unsafe.Pointer, attributeType C.ub4) (C.ub4,
 
 // ociBindByName calls OCIBindByName, then returns bind handle and error.
 func (stmt *Stmt) ociBindByName(name []byte, bind *bindStruct) error {
+	mode := C.ub4(C.OCI_DEFAULT)
+	if bind.returning != nil {
+		// values are provided by the OCIBindDynamic callbacks
+		mode = C.OCI_DATA_AT_EXEC
+	}
+
 	result := C.OCIBindByName(
 		stmt.stmt,                      // The statement handle
 		&bind.bindHandle,               // The bind handle that is implicitly allocated by this call. The handle is freed implicitly when the statement handle is deallocated.
@@ -979,9 +1904,9 @@ func (stmt *Stmt) ociBindByName(name []byte, bind *bindStruct) error {
 		unsafe.Pointer(bind.indicator), // Pointer to an indicator variable or array
 		bind.length,                    // lengths are in bytes in general
 		nil,                            // Pointer to the array of column-level return codes
-		0,                              // A maximum array length parameter
-		nil,                            // Current array length parameter
-		C.OCI_DEFAULT,                  // The mode. Recommended to set to OCI_DEFAULT, which makes the bind variable have the same encoding as its statement.
+		bind.plsqlArrayMax,             // A maximum array length parameter
+		bind.plsqlArrayCurrent,         // Current array length parameter
+		mode,                           // The mode. Recommended to set to OCI_DEFAULT, which makes the bind variable have the same encoding as its statement.
 	)
 
 	return stmt.conn.getError(result)
@@ -989,6 +1914,12 @@ func (stmt *Stmt) ociBindByName(name []byte, bind *bindStruct) error {
 
 // ociBindByPos calls OCIBindByPos, then returns bind handle and error.
 func (stmt *Stmt) ociBindByPos(position C.ub4, bind *bindStruct) error {
+	mode := C.ub4(C.OCI_DEFAULT)
+	if bind.returning != nil {
+		// values are provided by the OCIBindDynamic callbacks
+		mode = C.OCI_DATA_AT_EXEC
+	}
+
 	result := C.OCIBindByPos(
 		stmt.stmt,                      // The statement handle
 		&bind.bindHandle,               // The bind handle that is implicitly allocated by this call. The handle is freed implicitly when the statement handle is deallocated.
@@ -1000,9 +1931,9 @@ func (stmt *Stmt) ociBindByPos(position C.ub4, bind *bindStruct) error {
 		unsafe.Pointer(bind.indicator), // Pointer to an indicator variable or array
 		bind.length,                    // lengths are in bytes in general
 		nil,                            // Pointer to the array of column-level return codes
-		0,                              // A maximum array length parameter
-		nil,                            // Current array length parameter
-		C.OCI_DEFAULT,                  // The mode. Recommended to set to OCI_DEFAULT, which makes the bind variable have the same encoding as its statement.
+		bind.plsqlArrayMax,             // A maximum array length parameter
+		bind.plsqlArrayCurrent,         // Current array length parameter
+		mode,                           // The mode. Recommended to set to OCI_DEFAULT, which makes the bind variable have the same encoding as its statement.
 	)
 
 	return stmt.conn.getError(result)
@@ -1021,5 +1952,28 @@ func (stmt *Stmt) ociStmtExecute(iters C.ub4, mode C.ub4) error {
 		mode,                // The mode: https://docs.oracle.com/cd/E11882_01/appdev.112/e10646/oci17msc001.htm#LNOCI17163
 	)
 
-	return stmt.conn.getError(result)
+	return stmt.getError(result)
+}
+
+// getError gets error from return result (sword) or OCIError like conn.getError,
+// and sets the parse error offset of an OraError
+func (stmt *Stmt) getError(result C.sword) error {
+	err := stmt.conn.getError(result)
+	oraError, ok := err.(*OraError)
+	if !ok {
+		return err
+	}
+
+	var offset C.ub2 // the parse error offset
+	C.OCIAttrGet(
+		unsafe.Pointer(stmt.stmt),     // statement handle
+		C.OCI_HTYPE_STMT,              // handle type
+		unsafe.Pointer(&offset),       // parse error offset
+		nil,                           // size of the attribute value
+		C.OCI_ATTR_PARSE_ERROR_OFFSET, // attribute type
+		stmt.conn.errHandle,           // an error handle
+	)
+	oraError.Offset = int(offset)
+
+	return oraError
 }
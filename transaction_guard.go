@@ -0,0 +1,80 @@
+package oci8
+
+// #include "oci8.go.h"
+import "C"
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"unsafe"
+)
+
+// LTXID returns the logical transaction ID of the session for Transaction Guard.
+// The LTXID is for the next commit, so get it before the commit. If the commit then fails with driver.ErrBadConn,
+// GetLTXIDOutcome with the LTXID tells if the commit happened.
+func (conn *Conn) LTXID() ([]byte, error) {
+	var session unsafe.Pointer
+	result := C.OCIAttrGet(
+		unsafe.Pointer(conn.svc), // service context handle
+		C.OCI_HTYPE_SVCCTX,       // handle type
+		unsafe.Pointer(&session), // user session handle
+		nil,                      // size of the attribute value
+		C.OCI_ATTR_SESSION,       // attribute type
+		conn.errHandle,           // an error handle
+	)
+	err := conn.getError(result)
+	if err != nil {
+		return nil, err
+	}
+
+	var ltxid *C.ub1
+	var size C.ub4
+	result = C.OCIAttrGet(
+		session,                // user session handle
+		C.OCI_HTYPE_SESSION,    // handle type
+		unsafe.Pointer(&ltxid), // pointer to the LTXID
+		&size,                  // size of the LTXID
+		C.OCI_ATTR_LTXID,       // attribute type
+		conn.errHandle,         // an error handle
+	)
+	err = conn.getError(result)
+	if err != nil {
+		return nil, err
+	}
+	if ltxid == nil || size < 1 {
+		return nil, errors.New("session does not have a LTXID")
+	}
+
+	return C.GoBytes(unsafe.Pointer(ltxid), C.int(size)), nil
+}
+
+// GetLTXIDOutcome calls DBMS_APP_CONT.GET_LTXID_OUTCOME on a connection from db to get the outcome of the
+// transaction with ltxid from a failed session. It also blocks the transaction from committing later,
+// so when Committed is false the transaction can be safely retried.
+// The user needs execute on DBMS_APP_CONT.
+func GetLTXIDOutcome(ctx context.Context, db *sql.DB, ltxid []byte) (*LTXIDOutcome, error) {
+	if len(ltxid) < 1 {
+		return nil, errors.New("LTXID is empty")
+	}
+
+	var committed int64
+	var userCallCompleted int64
+	_, err := db.ExecContext(ctx, `
+declare
+  committed boolean;
+  user_call_completed boolean;
+begin
+  DBMS_APP_CONT.GET_LTXID_OUTCOME(:1, committed, user_call_completed);
+  :2 := case when committed then 1 else 0 end;
+  :3 := case when user_call_completed then 1 else 0 end;
+end;`, ltxid, sql.Out{Dest: &committed}, sql.Out{Dest: &userCallCompleted})
+	if err != nil {
+		return nil, err
+	}
+
+	return &LTXIDOutcome{
+		Committed:         committed != 0,
+		UserCallCompleted: userCallCompleted != 0,
+	}, nil
+}
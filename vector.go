@@ -0,0 +1,286 @@
+package oci8
+
+// #include "oci8.go.h"
+import "C"
+
+import (
+	"fmt"
+	"unsafe"
+)
+
+// vectorArray returns the OCI vector format, the number of dimensions, and a C copy of the values.
+// The C array must be freed with C.free.
+func vectorArray(values interface{}) (C.ub1, int, unsafe.Pointer, error) {
+	var format C.ub1
+	var dimensions int
+	var size int
+	var data unsafe.Pointer
+
+	switch array := values.(type) {
+	case []float32:
+		format = C.OCI_ATTR_VECTOR_FORMAT_FLOAT32
+		dimensions = len(array)
+		size = 4 * len(array)
+		if len(array) > 0 {
+			data = unsafe.Pointer(&array[0])
+		}
+	case []float64:
+		format = C.OCI_ATTR_VECTOR_FORMAT_FLOAT64
+		dimensions = len(array)
+		size = 8 * len(array)
+		if len(array) > 0 {
+			data = unsafe.Pointer(&array[0])
+		}
+	case []int8:
+		format = C.OCI_ATTR_VECTOR_FORMAT_INT8
+		dimensions = len(array)
+		size = len(array)
+		if len(array) > 0 {
+			data = unsafe.Pointer(&array[0])
+		}
+	case []byte:
+		format = C.OCI_ATTR_VECTOR_FORMAT_BINARY
+		dimensions = 8 * len(array)
+		size = len(array)
+		if len(array) > 0 {
+			data = unsafe.Pointer(&array[0])
+		}
+	default:
+		return 0, 0, nil, fmt.Errorf("vector values %T not supported, expected []float32, []float64, []int8, or []byte", values)
+	}
+
+	buffer := C.malloc(C.size_t(size + 1))
+	if size > 0 {
+		copy((*[1 << 30]byte)(buffer)[:size:size], (*[1 << 30]byte)(data)[:size:size])
+	}
+	return format, dimensions, buffer, nil
+}
+
+// vectorValues returns a Go slice with a copy of count values of the format from the C array
+func vectorValues(format C.ub1, count int, buffer unsafe.Pointer) (interface{}, error) {
+	switch format {
+	case C.OCI_ATTR_VECTOR_FORMAT_FLOAT32:
+		values := make([]float32, count)
+		if count > 0 {
+			copy(values, (*[1 << 28]float32)(buffer)[:count:count])
+		}
+		return values, nil
+	case C.OCI_ATTR_VECTOR_FORMAT_FLOAT64:
+		values := make([]float64, count)
+		if count > 0 {
+			copy(values, (*[1 << 27]float64)(buffer)[:count:count])
+		}
+		return values, nil
+	case C.OCI_ATTR_VECTOR_FORMAT_INT8:
+		values := make([]int8, count)
+		if count > 0 {
+			copy(values, (*[1 << 30]int8)(buffer)[:count:count])
+		}
+		return values, nil
+	case C.OCI_ATTR_VECTOR_FORMAT_BINARY:
+		return C.GoBytes(buffer, C.int(count)), nil
+	}
+	return nil, fmt.Errorf("vector format %v not supported", format)
+}
+
+// vectorElementSize returns the size in bytes of a vector value of the format
+func vectorElementSize(format C.ub1) int {
+	switch format {
+	case C.OCI_ATTR_VECTOR_FORMAT_FLOAT32:
+		return 4
+	case C.OCI_ATTR_VECTOR_FORMAT_FLOAT64:
+		return 8
+	}
+	return 1
+}
+
+// makeVectorBind makes a vector descriptor from a Vector or SparseVector.
+// Nil Values binds a null.
+func (stmt *Stmt) makeVectorBind(sbind *bindStruct, value interface{}) error {
+	vectorP, _, err := stmt.conn.ociDescriptorAlloc(C.OCI_DTYPE_VECTOR, 0)
+	if err != nil {
+		return err
+	}
+	sbind.dataType = C.SQLT_VEC
+	sbind.pbuf = unsafe.Pointer(vectorP)
+	sbind.maxSize = C.sb4(sizeOfNilPointer)
+	*sbind.length = C.ub2(sizeOfNilPointer)
+	vector := (*C.OCIVector)(*vectorP)
+
+	var result C.sword
+	switch aVector := value.(type) {
+	case Vector:
+		if aVector.Values == nil {
+			*sbind.indicator = -1 // set to null
+			return nil
+		}
+		format, dimensions, buffer, err := vectorArray(aVector.Values)
+		if err != nil {
+			return err
+		}
+		defer C.free(buffer)
+		result = C.OCIVectorFromArray(
+			vector,              // vector descriptor
+			stmt.conn.errHandle, // error handle
+			format,              // format of the values
+			C.ub4(dimensions),   // number of dimensions
+			buffer,              // the values
+			C.OCI_DEFAULT,       // mode
+		)
+
+	case SparseVector:
+		if aVector.Values == nil {
+			*sbind.indicator = -1 // set to null
+			return nil
+		}
+		format, count, buffer, err := vectorArray(aVector.Values)
+		if err != nil {
+			return err
+		}
+		defer C.free(buffer)
+		if format == C.OCI_ATTR_VECTOR_FORMAT_BINARY {
+			return fmt.Errorf("sparse vector values can not be []byte")
+		}
+		if count != len(aVector.Indices) {
+			return fmt.Errorf("sparse vector has %v indices and %v values", len(aVector.Indices), count)
+		}
+		indices := C.malloc(C.size_t(4*count + 1))
+		defer C.free(indices)
+		for i, index := range aVector.Indices {
+			(*[1 << 28]C.ub4)(indices)[i] = C.ub4(index)
+		}
+		result = C.OCIVectorFromSparseArray(
+			vector,                    // vector descriptor
+			stmt.conn.errHandle,       // error handle
+			format,                    // format of the values
+			C.ub4(aVector.Dimensions), // number of dimensions
+			C.ub4(count),              // number of non-zero values
+			indices,                   // indices of the non-zero values
+			buffer,                    // the non-zero values
+			C.OCI_DEFAULT,             // mode
+		)
+
+	default:
+		return fmt.Errorf("vector type %T not supported", value)
+	}
+
+	return stmt.conn.getError(result)
+}
+
+// ociVectorAttrGet calls OCIAttrGet with a vector descriptor
+func (conn *Conn) ociVectorAttrGet(vector *C.OCIVector, value unsafe.Pointer, attributeType C.ub4) error {
+	result := C.OCIAttrGet(
+		unsafe.Pointer(vector), // vector descriptor
+		C.OCI_DTYPE_VECTOR,     // descriptor type
+		value,                  // the attribute value
+		nil,                    // size not needed
+		attributeType,          // the attribute type
+		conn.errHandle,         // error handle
+	)
+	return conn.getError(result)
+}
+
+// ociVectorValue returns the values of a vector descriptor:
+// []float32, []float64, []int8, or []byte for a dense vector, or a SparseVector
+func (conn *Conn) ociVectorValue(vector *C.OCIVector) (interface{}, error) {
+	var dimensions C.ub4
+	err := conn.ociVectorAttrGet(vector, unsafe.Pointer(&dimensions), C.OCI_ATTR_VECTOR_DIMENSION)
+	if err != nil {
+		return nil, err
+	}
+	var format C.ub1
+	err = conn.ociVectorAttrGet(vector, unsafe.Pointer(&format), C.OCI_ATTR_VECTOR_DATA_FORMAT)
+	if err != nil {
+		return nil, err
+	}
+	var property C.ub1
+	err = conn.ociVectorAttrGet(vector, unsafe.Pointer(&property), C.OCI_ATTR_VECTOR_PROPERTY)
+	if err != nil {
+		return nil, err
+	}
+
+	count := int(dimensions)
+	if format == C.OCI_ATTR_VECTOR_FORMAT_BINARY {
+		count = int(dimensions) / 8
+	}
+	buffer := C.malloc(C.size_t(vectorElementSize(format)*count + 1))
+	defer C.free(buffer)
+
+	if property&C.OCI_ATTR_VECTOR_PROPERTY_SPARSE == 0 {
+		result := C.OCIVectorToArray(
+			vector,         // vector descriptor
+			conn.errHandle, // error handle
+			format,         // format of the values
+			&dimensions,    // number of dimensions
+			buffer,         // the values
+			C.OCI_DEFAULT,  // mode
+		)
+		err = conn.getError(result)
+		if err != nil {
+			return nil, err
+		}
+		return vectorValues(format, count, buffer)
+	}
+
+	// the number of non-zero values is at most the number of dimensions
+	indices := C.malloc(C.size_t(4*count + 1))
+	defer C.free(indices)
+	valueCount := C.ub4(count)
+	result := C.OCIVectorToSparseArray(
+		vector,         // vector descriptor
+		conn.errHandle, // error handle
+		format,         // format of the values
+		&dimensions,    // number of dimensions
+		&valueCount,    // size of the arrays, returns the number of non-zero values
+		indices,        // indices of the non-zero values
+		buffer,         // the non-zero values
+		C.OCI_DEFAULT,  // mode
+	)
+	err = conn.getError(result)
+	if err != nil {
+		return nil, err
+	}
+
+	sparseVector := SparseVector{
+		Dimensions: int(dimensions),
+		Indices:    make([]uint32, int(valueCount)),
+	}
+	for i := range sparseVector.Indices {
+		sparseVector.Indices[i] = uint32((*[1 << 28]C.ub4)(indices)[i])
+	}
+	sparseVector.Values, err = vectorValues(format, int(valueCount), buffer)
+	if err != nil {
+		return nil, err
+	}
+	return sparseVector, nil
+}
+
+// outputVector sets a *Vector or *SparseVector OUT parameter from the vector descriptor of the bind
+func (stmt *Stmt) outputVector(bind *bindStruct) error {
+	var value interface{}
+	if *bind.indicator != -1 {
+		var err error
+		value, err = stmt.conn.ociVectorValue(*(**C.OCIVector)(bind.pbuf))
+		if err != nil {
+			return err
+		}
+	}
+
+	switch dest := bind.out.Dest.(type) {
+	case *Vector:
+		if sparseVector, ok := value.(SparseVector); ok {
+			return fmt.Errorf("can not set sparse vector with %v dimensions into *Vector", sparseVector.Dimensions)
+		}
+		dest.Values = value
+	case *SparseVector:
+		switch aVector := value.(type) {
+		case nil:
+			*dest = SparseVector{}
+		case SparseVector:
+			*dest = aVector
+		default:
+			return fmt.Errorf("can not set dense vector into *SparseVector")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,263 @@
+package oci8
+
+// #include "oci8.go.h"
+import "C"
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"time"
+	"unsafe"
+)
+
+// WithXID returns a context that makes BeginTx start a new two-phase commit transaction branch with xid.
+// If the branch is detached, it is rolled back when it is not resumed within timeout.
+func WithXID(ctx context.Context, xid XID, timeout time.Duration) context.Context {
+	return context.WithValue(ctx, xidContextKey{}, &xidContext{xid: xid, timeout: timeout})
+}
+
+// WithXIDResume returns a context that makes BeginTx resume the detached transaction branch with xid.
+// BeginTx waits up to timeout for the branch to become available.
+func WithXIDResume(ctx context.Context, xid XID, timeout time.Duration) context.Context {
+	return context.WithValue(ctx, xidContextKey{}, &xidContext{xid: xid, timeout: timeout, resume: true})
+}
+
+// toOCI returns the XID as an OCI XID
+func (xid XID) toOCI() (C.XID, error) {
+	var ociXID C.XID
+	if len(xid.GlobalTransactionID) < 1 || len(xid.GlobalTransactionID) > C.MAXGTRIDSIZE {
+		return ociXID, fmt.Errorf("XID global transaction ID length %v is not between 1 and %v", len(xid.GlobalTransactionID), C.MAXGTRIDSIZE)
+	}
+	if len(xid.BranchQualifier) > C.MAXBQUALSIZE {
+		return ociXID, fmt.Errorf("XID branch qualifier length %v is more than %v", len(xid.BranchQualifier), C.MAXBQUALSIZE)
+	}
+
+	ociXID.formatID = C.long(xid.FormatID)
+	ociXID.gtrid_length = C.long(len(xid.GlobalTransactionID))
+	ociXID.bqual_length = C.long(len(xid.BranchQualifier))
+	data := (*[C.XIDDATASIZE]byte)(unsafe.Pointer(&ociXID.data[0]))
+	copy(data[:], xid.GlobalTransactionID)
+	copy(data[len(xid.GlobalTransactionID):], xid.BranchQualifier)
+
+	return ociXID, nil
+}
+
+// ociTransHandleXID allocates a transaction handle with the XID and sets it as the transaction of the service context
+func (conn *Conn) ociTransHandleXID(xid XID) (unsafe.Pointer, error) {
+	ociXID, err := xid.toOCI()
+	if err != nil {
+		return nil, err
+	}
+
+	trans, _, err := conn.ociHandleAlloc(C.OCI_HTYPE_TRANS, 0)
+	if err != nil {
+		return nil, fmt.Errorf("allocate transaction handle error: %v", err)
+	}
+
+	err = conn.ociAttrSet(*trans, C.OCI_HTYPE_TRANS, unsafe.Pointer(&ociXID), C.ub4(unsafe.Sizeof(ociXID)), C.OCI_ATTR_XID)
+	if err != nil {
+		C.OCIHandleFree(*trans, C.OCI_HTYPE_TRANS)
+		return nil, fmt.Errorf("XID attribute set error: %v", err)
+	}
+
+	err = conn.ociAttrSet(unsafe.Pointer(conn.svc), C.OCI_HTYPE_SVCCTX, *trans, 0, C.OCI_ATTR_TRANS)
+	if err != nil {
+		C.OCIHandleFree(*trans, C.OCI_HTYPE_TRANS)
+		return nil, fmt.Errorf("transaction attribute set error: %v", err)
+	}
+
+	return *trans, nil
+}
+
+// ociTransHandleFree removes the transaction handle from the service context then frees it
+func (conn *Conn) ociTransHandleFree(trans unsafe.Pointer) {
+	err := conn.ociAttrSet(unsafe.Pointer(conn.svc), C.OCI_HTYPE_SVCCTX, nil, 0, C.OCI_ATTR_TRANS)
+	if err != nil {
+		conn.logger.Print("transaction attribute set error: ", err)
+	}
+	C.OCIHandleFree(trans, C.OCI_HTYPE_TRANS)
+}
+
+// beginTxXID starts or resumes the two-phase commit transaction branch from the context
+func (conn *Conn) beginTxXID(ctx context.Context, xidCtx *xidContext, transactionMode C.ub4) (driver.Tx, error) {
+	trans, err := conn.ociTransHandleXID(xidCtx.xid)
+	if err != nil {
+		return nil, err
+	}
+
+	flags := C.ub4(C.OCI_TRANS_NEW) | transactionMode
+	if xidCtx.resume {
+		flags = C.OCI_TRANS_RESUME
+	}
+
+	conn.callBegin(ctx)
+	result := C.OCITransStart(
+		conn.svc,                            // service context
+		conn.errHandle,                      // error handle
+		C.uword(xidCtx.timeout/time.Second), // seconds a detached branch is kept, or seconds to wait to resume
+		flags,                               // OCI_TRANS_NEW with the isolation mode, or OCI_TRANS_RESUME
+	)
+	conn.callEnd()
+	if result != C.OCI_SUCCESS {
+		err = conn.getError(result)
+		conn.ociTransHandleFree(trans)
+		return nil, err
+	}
+
+	xid := xidCtx.xid
+	conn.inTransaction = true
+	conn.tx = &Tx{conn: conn, xid: &xid, transHandle: trans}
+
+	return conn.tx, nil
+}
+
+// XID returns the XID of the two-phase commit transaction branch, or nil if the transaction was not started with an XID
+func (tx *Tx) XID() *XID {
+	if tx.xid == nil {
+		return nil
+	}
+	xid := *tx.xid
+	return &xid
+}
+
+// Prepare prepares the two-phase commit transaction branch, the first phase.
+// Commit then commits the branch with OCI_TRANS_TWOPHASE.
+// Returns true if the branch made no changes, the branch is then complete and Commit does nothing.
+func (tx *Tx) Prepare() (bool, error) {
+	if tx.conn.tx != tx {
+		return false, sql.ErrTxDone
+	}
+	if tx.xid == nil {
+		return false, errors.New("prepare needs a transaction started with an XID")
+	}
+
+	result := C.OCITransPrepare(tx.conn.svc, tx.conn.errHandle, C.OCI_DEFAULT)
+	if result == C.OCI_SUCCESS_WITH_INFO {
+		// ORA-24767: transaction branch prepare returns read-only
+		tx.completed = true
+		tx.end()
+		return true, nil
+	}
+	if result != C.OCI_SUCCESS {
+		return false, tx.conn.getError(result)
+	}
+
+	tx.prepared = true
+	return false, nil
+}
+
+// Detach detaches the two-phase commit transaction branch from the session.
+// The branch can then be resumed in another session with WithXIDResume, or committed or rolled back with CommitXID or RollbackXID after it is prepared.
+// After Detach, Rollback does nothing so the sql.Tx can be rolled back to release the connection.
+func (tx *Tx) Detach() error {
+	if tx.conn.tx != tx {
+		return sql.ErrTxDone
+	}
+	if tx.xid == nil {
+		return errors.New("detach needs a transaction started with an XID")
+	}
+
+	result := C.OCITransDetach(tx.conn.svc, tx.conn.errHandle, C.OCI_DEFAULT)
+	if result != C.OCI_SUCCESS {
+		return tx.conn.getError(result)
+	}
+
+	tx.detached = true
+	tx.end()
+	return nil
+}
+
+// commitXID commits the two-phase commit transaction branch, with OCI_TRANS_TWOPHASE if prepared
+func (tx *Tx) commitXID() error {
+	if tx.completed {
+		return nil
+	}
+	if tx.detached {
+		return errors.New("transaction branch is detached")
+	}
+	if tx.conn.tx != tx {
+		return sql.ErrTxDone
+	}
+
+	flags := C.ub4(C.OCI_DEFAULT)
+	if tx.prepared {
+		flags = C.OCI_TRANS_TWOPHASE
+	}
+	result := C.OCITransCommit(tx.conn.svc, tx.conn.errHandle, flags)
+	tx.end()
+	if result != C.OCI_SUCCESS {
+		return tx.conn.getError(result)
+	}
+	return nil
+}
+
+// rollbackXID rolls back the two-phase commit transaction branch
+func (tx *Tx) rollbackXID() error {
+	if tx.completed || tx.detached {
+		return nil
+	}
+	if tx.conn.tx != tx {
+		return sql.ErrTxDone
+	}
+
+	result := C.OCITransRollback(tx.conn.svc, tx.conn.errHandle, C.OCI_DEFAULT)
+	tx.end()
+	if result != C.OCI_SUCCESS {
+		return tx.conn.getError(result)
+	}
+	return nil
+}
+
+// end ends the transaction on the connection and frees the transaction handle
+func (tx *Tx) end() {
+	tx.conn.inTransaction = false
+	tx.conn.tx = nil
+	tx.savepoints = nil
+	if tx.transHandle != nil {
+		tx.conn.ociTransHandleFree(tx.transHandle)
+		tx.transHandle = nil
+	}
+}
+
+// CommitXID commits the prepared two-phase commit transaction branch with xid.
+// It can be used in any session, like for recovery after the session that prepared the branch is gone.
+func (conn *Conn) CommitXID(xid XID) error {
+	if conn.inTransaction {
+		return errors.New("commit by XID can not be used in a transaction")
+	}
+
+	trans, err := conn.ociTransHandleXID(xid)
+	if err != nil {
+		return err
+	}
+	defer conn.ociTransHandleFree(trans)
+
+	result := C.OCITransCommit(conn.svc, conn.errHandle, C.OCI_TRANS_TWOPHASE)
+	if result != C.OCI_SUCCESS {
+		return conn.getError(result)
+	}
+	return nil
+}
+
+// RollbackXID rolls back the prepared or detached two-phase commit transaction branch with xid.
+// It can be used in any session, like for recovery after the session that prepared the branch is gone.
+func (conn *Conn) RollbackXID(xid XID) error {
+	if conn.inTransaction {
+		return errors.New("rollback by XID can not be used in a transaction")
+	}
+
+	trans, err := conn.ociTransHandleXID(xid)
+	if err != nil {
+		return err
+	}
+	defer conn.ociTransHandleFree(trans)
+
+	result := C.OCITransRollback(conn.svc, conn.errHandle, C.OCI_DEFAULT)
+	if result != C.OCI_SUCCESS {
+		return conn.getError(result)
+	}
+	return nil
+}
@@ -0,0 +1,98 @@
+package oci8
+
+// #include "oci8.go.h"
+import "C"
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"unsafe"
+)
+
+// Scan implements sql.Scanner, a null XMLType sets Document to nil
+func (xmlType *XMLType) Scan(src interface{}) error {
+	switch value := src.(type) {
+	case nil:
+		xmlType.Document = nil
+	case string:
+		xmlType.Document = value
+	case []byte:
+		xmlType.Document = append([]byte(nil), value...)
+	default:
+		return fmt.Errorf("can not scan %T into XMLType", src)
+	}
+	return nil
+}
+
+// Reader returns an io.Reader of the document
+func (xmlType XMLType) Reader() io.Reader {
+	switch document := xmlType.Document.(type) {
+	case string:
+		return strings.NewReader(document)
+	case []byte:
+		return bytes.NewReader(document)
+	}
+	return strings.NewReader("")
+}
+
+// String returns the document as a string
+func (xmlType XMLType) String() string {
+	switch document := xmlType.Document.(type) {
+	case string:
+		return document
+	case []byte:
+		return string(document)
+	}
+	return ""
+}
+
+// isXMLType returns true if the object type of the column parameter is SYS.XMLTYPE
+func (conn *Conn) isXMLType(param *C.OCIParam) (bool, error) {
+	schemaName, err := conn.paramString(param, C.OCI_ATTR_SCHEMA_NAME)
+	if err != nil {
+		return false, err
+	}
+	typeName, err := conn.paramString(param, C.OCI_ATTR_TYPE_NAME)
+	if err != nil {
+		return false, err
+	}
+	return schemaName == "SYS" && typeName == "XMLTYPE", nil
+}
+
+// makeXMLTypeBind writes the document to a temporary CLOB, which is freed with the bind after the execute.
+// A nil Document binds a null.
+func (stmt *Stmt) makeXMLTypeBind(sbind *bindStruct, value XMLType) error {
+	var document []byte
+	switch data := value.Document.(type) {
+	case nil:
+		sbind.dataType = C.SQLT_AFC
+		sbind.pbuf = nil
+		sbind.maxSize = 0
+		*sbind.indicator = -1 // set to null
+		return nil
+	case string:
+		document = []byte(data)
+	case []byte:
+		document = data
+	default:
+		return fmt.Errorf("XMLType Document %T not supported, expected string or []byte", value.Document)
+	}
+
+	lobP, _, err := stmt.conn.ociDescriptorAlloc(C.OCI_DTYPE_LOB, 0)
+	if err != nil {
+		return err
+	}
+	sbind.dataType = C.SQLT_CLOB
+	sbind.pbuf = unsafe.Pointer(lobP)
+	sbind.maxSize = C.sb4(sizeOfNilPointer)
+	*sbind.length = C.ub2(sizeOfNilPointer)
+	lobLocator := (**C.OCILobLocator)(sbind.pbuf)
+	err = stmt.conn.ociLobCreateTemporary(*lobLocator, C.SQLCS_IMPLICIT, C.OCI_TEMP_CLOB)
+	if err != nil {
+		return err
+	}
+	sbind.temporaryLobConn = stmt.conn
+	return stmt.conn.ociLobWrite(*lobLocator, C.SQLCS_IMPLICIT, document)
+}